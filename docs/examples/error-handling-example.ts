@@ -101,6 +101,8 @@ function getUserFriendlyMessage(error: KSUIDError): string {
     [KSUID_ERROR_CODES.INVALID_INPUT]: "The input cannot be empty or null.",
     [KSUID_ERROR_CODES.MALFORMED_DATA]: "The data appears to be corrupted.",
     [KSUID_ERROR_CODES.CORRUPTION_DETECTED]: "Data corruption was detected.",
+    [KSUID_ERROR_CODES.TIMESTAMP_EXPIRED]: "The ID has expired.",
+    [KSUID_ERROR_CODES.TIMESTAMP_IN_FUTURE]: "The ID is dated in the future.",
     [KSUID_ERROR_CODES.SEQUENCE_EXHAUSTED]:
       "The sequence has reached its maximum capacity.",
     [KSUID_ERROR_CODES.OPERATION_FAILED]:
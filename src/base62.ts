@@ -1,12 +1,22 @@
 import { Buffer } from "buffer";
 import { KSUIDError } from "./errors";
 
-const BASE62_ALPHABET =
+/**
+ * The 62 characters used by the encoder, digits then uppercase then lowercase
+ * letters. This is the ordering of the reference Go implementation; because it
+ * follows ASCII order, encoded strings sort in the same order as the KSUIDs.
+ */
+export const BASE62_ALPHABET =
   "0123456789ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz";
 const BASE = BigInt(62);
 const KSUID_BYTE_LENGTH = 20;
 const ENCODED_STRING_LENGTH = 27;
 
+// Character codes of the alphabet and a scratch area of 32-bit words, so
+// encodeInto() can convert without allocating.
+const ALPHABET_CODES = Buffer.from(BASE62_ALPHABET, "ascii");
+const WORDS = new Uint32Array(KSUID_BYTE_LENGTH / 4);
+
 // Pre-compute a map for character-to-value lookups for efficient decoding.
 const CHAR_MAP: Map<string, bigint> = new Map();
 for (let i = 0; i < BASE62_ALPHABET.length; i++) {
@@ -52,6 +62,38 @@ export class Base62 {
     return encoded.padStart(ENCODED_STRING_LENGTH, "0");
   }
 
+  /**
+   * Writes the 27-character Base62 encoding of a 20-byte buffer into dst at
+   * offset as ASCII bytes. The conversion runs on 32-bit words rather than a
+   * BigInt, so nothing is allocated. dst must have room for 27 bytes.
+   */
+  static encodeInto(buffer: Buffer, dst: Buffer, offset: number): void {
+    if (buffer.length !== KSUID_BYTE_LENGTH) {
+      throw KSUIDError.invalidBufferLength(
+        buffer,
+        KSUID_BYTE_LENGTH,
+        "KSUID buffer"
+      );
+    }
+
+    for (let i = 0; i < WORDS.length; i++) {
+      WORDS[i] = buffer.readUInt32BE(i * 4);
+    }
+
+    // Long division of the 160-bit value by 62, one output digit per pass,
+    // from the least significant digit. Each partial dividend stays below
+    // 62 * 2^32, well within the exact integer range of a double.
+    for (let pos = ENCODED_STRING_LENGTH - 1; pos >= 0; pos--) {
+      let remainder = 0;
+      for (let i = 0; i < WORDS.length; i++) {
+        const value = remainder * 0x100000000 + WORDS[i];
+        WORDS[i] = Math.floor(value / 62);
+        remainder = value % 62;
+      }
+      dst[offset + pos] = ALPHABET_CODES[remainder];
+    }
+  }
+
   /**
    * Decodes a Base62 string into a 20-byte buffer.
    * @param str The Base62 string to decode.
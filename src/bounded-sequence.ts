@@ -0,0 +1,82 @@
+import { KSUID } from "./ksuid";
+import { KSUIDError, KSUID_ERROR_CODES } from "./errors";
+
+const MAX_PAYLOAD = (1n << 128n) - 1n;
+
+/**
+ * BoundedSequence produces exactly count ordered KSUIDs from a seed: the seed
+ * itself followed by its successive next() values. It suits pre-allocating a
+ * block of IDs, for example for a batch insert.
+ *
+ * The whole block shares the seed's timestamp. The constructor throws if the
+ * block would overflow the payload into the next second.
+ *
+ * ```typescript
+ * const seq = new BoundedSequence({ seed: KSUID.random(), count: 100 });
+ * while (seq.remaining() > 0) {
+ *   const id = seq.next();
+ * }
+ * ```
+ */
+export class BoundedSequence {
+  private current: KSUID;
+  private left: number;
+
+  constructor(options: { seed: KSUID; count: number }) {
+    const { seed, count } = options;
+    if (!Number.isSafeInteger(count) || count < 0) {
+      throw new KSUIDError(
+        `Invalid count: must be a non-negative integer, got ${count}`,
+        KSUID_ERROR_CODES.INVALID_INPUT,
+        {
+          input: count,
+          expected: "non-negative integer",
+          actual: String(count),
+        }
+      );
+    }
+
+    const payload = BigInt("0x" + seed.payload.toString("hex"));
+    if (count > 0 && payload + BigInt(count - 1) > MAX_PAYLOAD) {
+      throw new KSUIDError(
+        `Invalid count: ${count} KSUIDs from ${seed.toString()} would overflow into the next second`,
+        KSUID_ERROR_CODES.INVALID_INPUT,
+        {
+          input: count,
+          expected: `at most ${MAX_PAYLOAD - payload + 1n}`,
+          actual: String(count),
+        }
+      );
+    }
+
+    this.current = seed;
+    this.left = count;
+  }
+
+  /**
+   * Next returns the next KSUID of the block. Throws a KSUIDError with code
+   * SEQUENCE_EXHAUSTED once all count KSUIDs have been returned.
+   */
+  next(): KSUID {
+    if (this.left === 0) {
+      throw new KSUIDError(
+        "Sequence exhausted: all KSUIDs in the block have been generated",
+        KSUID_ERROR_CODES.SEQUENCE_EXHAUSTED
+      );
+    }
+
+    const id = this.current;
+    this.left--;
+    if (this.left > 0) {
+      this.current = id.next();
+    }
+    return id;
+  }
+
+  /**
+   * Returns how many KSUIDs next() can still return.
+   */
+  remaining(): number {
+    return this.left;
+  }
+}
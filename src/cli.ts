@@ -1,30 +1,64 @@
 #!/usr/bin/env node
 
-import { KSUID } from "./ksuid";
+import * as crypto from "crypto";
+import * as fs from "fs";
+import { KSUID, EPOCH, timestampToTime } from "./ksuid";
+import { sort } from "./sort";
 import { isKSUIDError } from "./errors";
 
 interface CLIArgs {
   count: number;
+  countSet: boolean;
   format: string;
   template: string;
   verbose: boolean;
+  reverse: boolean;
+  skipInvalid: boolean;
+  strict: boolean;
+  quiet: boolean;
+  hex: boolean;
+  unique: boolean;
+  timestamp: string;
+  payload: string;
+  stdin: boolean;
   args: string[];
 }
 
+// Subcommands whose operands are KSUIDs to check. After one of these, an
+// unrecognized token starting with "-" is an operand rather than an option,
+// so it is reported as invalid instead of being dropped.
+const OPERAND_COMMANDS = new Set(["validate", "verify"]);
+
 function parseArgs(args: string[]): CLIArgs {
   const parsed: CLIArgs = {
     count: 1,
+    countSet: false,
     format: "string",
     template: "",
     verbose: false,
+    reverse: false,
+    skipInvalid: false,
+    strict: false,
+    quiet: false,
+    hex: false,
+    unique: false,
+    timestamp: "",
+    payload: "",
+    stdin: false,
     args: [],
   };
 
+  let optionsEnded = false;
   for (let i = 2; i < args.length; i++) {
     const arg = args[i];
 
-    if (arg === "-n" && i + 1 < args.length) {
+    if (optionsEnded) {
+      parsed.args.push(arg);
+    } else if (arg === "--") {
+      optionsEnded = true;
+    } else if (arg === "-n" && i + 1 < args.length) {
       parsed.count = parseInt(args[++i], 10);
+      parsed.countSet = true;
       if (isNaN(parsed.count) || parsed.count <= 0) {
         parsed.count = 1;
       }
@@ -32,12 +66,30 @@ function parseArgs(args: string[]): CLIArgs {
       parsed.format = args[++i];
     } else if (arg === "-t" && i + 1 < args.length) {
       parsed.template = args[++i];
-    } else if (arg === "-v") {
+    } else if (arg === "-v" || arg === "--verbose") {
       parsed.verbose = true;
+    } else if (arg === "-r" || arg === "--reverse") {
+      parsed.reverse = true;
+    } else if (arg === "--skip-invalid") {
+      parsed.skipInvalid = true;
+    } else if (arg === "--strict") {
+      parsed.strict = true;
+    } else if (arg === "--quiet") {
+      parsed.quiet = true;
+    } else if (arg === "--hex") {
+      parsed.hex = true;
+    } else if (arg === "--unique") {
+      parsed.unique = true;
+    } else if (arg === "--timestamp" && i + 1 < args.length) {
+      parsed.timestamp = args[++i];
+    } else if (arg === "--payload" && i + 1 < args.length) {
+      parsed.payload = args[++i];
     } else if (arg === "--help" || arg === "-h") {
       printHelp();
       process.exit(0);
-    } else if (!arg.startsWith("-")) {
+    } else if (arg === "-") {
+      parsed.stdin = true;
+    } else if (!arg.startsWith("-") || OPERAND_COMMANDS.has(parsed.args[0])) {
       parsed.args.push(arg);
     }
   }
@@ -47,30 +99,92 @@ function parseArgs(args: string[]): CLIArgs {
 
 function printHelp(): void {
   console.log(`Usage: ksuid [options] [KSUIDs...]
+       ksuid validate [--] [KSUIDs...]
+       ksuid verify [--quiet] [--] [KSUIDs...]
+       ksuid sort [-r] [--skip-invalid] [--unique] [-v] < FILE
+       ksuid stats < FILE
+       ksuid decode [--hex] < FILE
 
 Generate and inspect KSUIDs.
 
+Commands:
+  validate   Check that every argument (or stdin line) is a canonical KSUID.
+             Prints nothing and exits 0 when all are valid; otherwise prints
+             each offending value to stderr and exits 1. Having no values at
+             all also fails. Values starting with "-" are checked like any
+             other; -- ends option parsing.
+  verify     Like validate, but stops at the first invalid value, printing
+             only that one to stderr (nothing with --quiet) and exiting 1.
+  sort       Read one KSUID per line from stdin and print them in
+             chronological order. An invalid line is reported with its line
+             number on stderr and exits 1, unless --skip-invalid is given.
+  decode     Read raw KSUIDs from stdin, 20 bytes each as written by -f raw,
+             and print one base62 KSUID per line. With --hex the input is
+             hex text instead (whitespace ignored), as written by -f hex.
+             Fails without output unless the input is a whole number of
+             KSUIDs.
+  stats      Read one KSUID per line from stdin and print the total and
+             distinct counts, the earliest and latest times, the span
+             between them, and the number of invalid lines.
+
 Options:
   -n NUM     Generate NUM KSUIDs (default: 1)
-  -f FORMAT  Output format: string, inspect, time, timestamp, payload, raw, template (default: string)
+  -f FORMAT  Output format: string, inspect, json, time, timestamp, payload, raw, hex, base64, template (default: string)
   -t TEXT    Template for custom formatting (use with -f template)
-  -v         Verbose mode (show KSUID before formatted output)
+  -v, --verbose   Verbose mode (show KSUID before formatted output)
+  -r, --reverse   Sort in descending order (sort only)
+  --skip-invalid  Drop invalid lines instead of failing (sort only)
+  --unique        Drop duplicate KSUIDs; -v reports how many (sort only)
+  --strict        Stop at the first invalid stdin line (inspect only)
+  --quiet         Print nothing and rely on the exit status (verify only)
+  --hex           Read hex text instead of raw bytes (decode only)
+  --timestamp TIME  Generate KSUIDs at TIME, given as Unix seconds or RFC 3339
+  --payload HEX     Generate KSUIDs with this payload (32 hex characters)
   -h, --help Show this help message
 
 Formats:
   string     Base62 string representation (default)
   inspect    Detailed breakdown of KSUID components
+  json       Inspect fields as one JSON object per line
   time       Human readable timestamp
   timestamp  Unix timestamp (seconds since epoch)  
   payload    Raw payload bytes
-  raw        Raw KSUID bytes
+  raw        Raw KSUID bytes, 20 per KSUID with no separators
+  hex        Raw KSUID bytes as 40 hex characters
+  base64     Raw KSUID bytes as 28 base64 characters
+
+With -f inspect and no KSUID arguments, -n, --timestamp or --payload,
+KSUIDs are read from stdin, one per line, when it is a pipe or a file or
+when - is given; an empty pipe generates a KSUID instead. Blank lines are
+skipped and invalid lines are reported on stderr with their line number;
+the rest are still printed and the exit status is 1, unless --strict stops
+at the first one.
+
+Templates (-f template -t TEXT) substitute {{ .String }}, {{ .Raw }},
+{{ .Time }}, {{ .Timestamp }} and {{ .Payload }}. A field can be piped
+through functions: hex (lowercase hex of .Raw, .Payload or a number), unix
+(Unix seconds of .Time) and rfc3339 (.Time without fractional seconds), as
+in {{ .Payload | hex }} or {{ .Time | unix }}.
+
+KSUID arguments are detected by length: 27 characters are base62, 28 are
+base64 and 40 are hex, so any of the output encodings can be read back.
 
 Examples:
   ksuid                           Generate one KSUID
   ksuid -n 5                      Generate five KSUIDs
+  ksuid -n 3 --timestamp 2024-01-01T00:00:00Z     Generate three KSUIDs at a fixed time
+  ksuid --timestamp 1495004740 --payload 669f7efd7b6fe812278486085878563d
+                                                   Build a KSUID from its parts
   ksuid -f inspect 0o5Fs0EELR0fUjHjbCnEtdUwx3e    Inspect a specific KSUID
+  ksuid -f json -n 3                               Inspect new KSUIDs as NDJSON
   ksuid -f time 0o5Fs0EELR0fUjHjbCnEtdUwx3e        Show timestamp as readable date
-  ksuid -v -f payload 0o5Fs0EELR0fUjHjbCnEtdUwx3e  Show payload with verbose output`);
+  ksuid -v -f payload 0o5Fs0EELR0fUjHjbCnEtdUwx3e  Show payload with verbose output
+  ksuid validate "$id" && echo ok                  Validate a KSUID in a script
+  ksuid verify --quiet "$id" || exit 1             Fail a CI step on a bad KSUID
+  ksuid sort -r < ids.txt                          Sort KSUIDs, newest first
+  ksuid -f raw -n 3 | ksuid decode                 Round trip through raw bytes
+  ksuid stats < ids.txt                            Summarize the KSUIDs in a file
+  ksuid -f inspect < ids.txt                       Inspect KSUIDs from a file`);
 }
 
 function printString(ksuid: KSUID): void {
@@ -86,7 +200,7 @@ REPRESENTATION:
 
 COMPONENTS:
 
-       Time: ${new Date((ksuid.timestamp + 1400000000) * 1000).toISOString()}
+       Time: ${timestampToTime(ksuid.timestamp).toISOString()}
   Timestamp: ${ksuid.timestamp}
     Payload: ${ksuid.payload.toString("hex").toUpperCase()}
 
@@ -94,9 +208,22 @@ COMPONENTS:
   console.log(inspectFormat);
 }
 
+// Prints the inspect fields as one JSON object per line, so several KSUIDs
+// form newline-delimited JSON.
+function printJSON(ksuid: KSUID): void {
+  console.log(
+    JSON.stringify({
+      ksuid: ksuid.toString(),
+      raw: ksuid.toBuffer().toString("hex").toUpperCase(),
+      time: timestampToTime(ksuid.timestamp).toISOString(),
+      timestamp: ksuid.timestamp,
+      payload: ksuid.payload.toString("hex").toUpperCase(),
+    })
+  );
+}
+
 function printTime(ksuid: KSUID): void {
-  const timestamp = (ksuid.timestamp + 1400000000) * 1000;
-  console.log(new Date(timestamp).toISOString());
+  console.log(timestampToTime(ksuid.timestamp).toISOString());
 }
 
 function printTimestamp(ksuid: KSUID): void {
@@ -111,37 +238,345 @@ function printRaw(ksuid: KSUID): void {
   process.stdout.write(ksuid.toBuffer());
 }
 
+function printHex(ksuid: KSUID): void {
+  console.log(ksuid.toBuffer().toString("hex"));
+}
+
+function printBase64(ksuid: KSUID): void {
+  console.log(ksuid.toBuffer().toString("base64"));
+}
+
+type TemplateValue = string | number | Buffer | Date;
+
+// Functions usable in templates as {{ .Field | name }}. Each returns
+// undefined when it cannot be applied to the value it is given.
+const TEMPLATE_FUNCTIONS: Record<
+  string,
+  (value: TemplateValue) => TemplateValue | undefined
+> = {
+  hex: value => {
+    if (Buffer.isBuffer(value)) {
+      return value.toString("hex");
+    }
+    return typeof value === "number" ? value.toString(16) : undefined;
+  },
+  unix: value =>
+    value instanceof Date ? Math.floor(value.getTime() / 1000) : undefined,
+  rfc3339: value =>
+    value instanceof Date
+      ? value.toISOString().replace(/\.\d{3}Z$/, "Z")
+      : undefined,
+};
+
+function formatTemplateValue(value: TemplateValue): string {
+  if (Buffer.isBuffer(value)) {
+    return value.toString("hex").toUpperCase();
+  }
+  if (value instanceof Date) {
+    return value.toISOString();
+  }
+  return String(value);
+}
+
 function printTemplate(ksuid: KSUID, template: string): void {
   if (!template) {
     console.error("Template format requires -t option");
     process.exit(1);
   }
 
-  const timestamp = (ksuid.timestamp + 1400000000) * 1000;
-  const data = {
+  const data: Record<string, TemplateValue> = {
     String: ksuid.toString(),
-    Raw: ksuid.toBuffer().toString("hex").toUpperCase(),
-    Time: new Date(timestamp).toISOString(),
+    Raw: ksuid.toBuffer(),
+    Time: timestampToTime(ksuid.timestamp),
     Timestamp: ksuid.timestamp,
-    Payload: ksuid.payload.toString("hex").toUpperCase(),
+    Payload: ksuid.payload,
   };
 
-  let result = template;
-  for (const [key, value] of Object.entries(data)) {
-    // Support both Go template syntax ({{ .Field }}) and simple syntax ({{ Field }})
-    const escapedKey = key.replace(/[.*+?^${}()|[\]\\]/g, "\\$&");
-    result = result.replace(
-      new RegExp("{{\\s*\\.?" + escapedKey + "\\s*}}", "g"),
-      value.toString()
+  // Supports both Go template syntax ({{ .Field }}) and simple syntax
+  // ({{ Field }}), each optionally piped through functions: {{ .Time | unix }}
+  const result = template.replace(
+    /{{\s*\.?(\w+)((?:\s*\|\s*\w+)*)\s*}}/g,
+    (match: string, field: string, pipeline: string) => {
+      if (!Object.prototype.hasOwnProperty.call(data, field)) {
+        return match;
+      }
+
+      let value = data[field];
+      const names = pipeline
+        .split("|")
+        .slice(1)
+        .map(part => part.trim());
+      for (const name of names) {
+        if (!Object.prototype.hasOwnProperty.call(TEMPLATE_FUNCTIONS, name)) {
+          console.error(`Unknown template function "${name}"`);
+          process.exit(1);
+        }
+        const applied = TEMPLATE_FUNCTIONS[name](value);
+        if (applied === undefined) {
+          console.error(
+            `Template function "${name}" cannot be applied to .${field}`
+          );
+          process.exit(1);
+        }
+        value = applied;
+      }
+      return formatTemplateValue(value);
+    }
+  );
+
+  console.log(result);
+}
+
+function readStdinLines(): string[] {
+  return fs
+    .readFileSync(0, "utf8")
+    .split(/\r?\n/)
+    .filter(line => line !== "");
+}
+
+function isCanonical(s: string): boolean {
+  try {
+    return KSUID.parse(s).toString() === s;
+  } catch {
+    return false;
+  }
+}
+
+function runValidate(inputs: string[]): number {
+  const values = inputs.length > 0 ? inputs : readStdinLines();
+  if (values.length === 0) {
+    console.error("validate: no KSUIDs given");
+    return 1;
+  }
+
+  let status = 0;
+  for (const value of values) {
+    if (!isCanonical(value)) {
+      console.error(value);
+      status = 1;
+    }
+  }
+  return status;
+}
+
+function runVerify(inputs: string[], quiet: boolean): number {
+  const values = inputs.length > 0 ? inputs : readStdinLines();
+  if (values.length === 0) {
+    if (!quiet) {
+      console.error("verify: no KSUIDs given");
+    }
+    return 1;
+  }
+
+  const invalid = values.find(value => !isCanonical(value));
+  if (invalid === undefined) {
+    return 0;
+  }
+  if (!quiet) {
+    console.error(invalid);
+  }
+  return 1;
+}
+
+function runSort(
+  reverse: boolean,
+  skipInvalid: boolean,
+  unique: boolean,
+  verbose: boolean
+): number {
+  const lines = fs.readFileSync(0, "utf8").split(/\r?\n/);
+
+  const ids: KSUID[] = [];
+  let status = 0;
+  lines.forEach((line, i) => {
+    if (line === "") {
+      return;
+    }
+    if (isCanonical(line)) {
+      ids.push(KSUID.parse(line));
+    } else if (!skipInvalid) {
+      console.error(`line ${i + 1}: invalid KSUID "${line}"`);
+      status = 1;
+    }
+  });
+  if (status !== 0) {
+    return status;
+  }
+
+  sort(ids);
+  if (unique) {
+    // Sorted order puts duplicates next to each other
+    let kept = 0;
+    for (const id of ids) {
+      if (kept === 0 || id.compare(ids[kept - 1]) !== 0) {
+        ids[kept++] = id;
+      }
+    }
+    if (verbose) {
+      console.error(`removed ${ids.length - kept} duplicate KSUIDs`);
+    }
+    ids.length = kept;
+  }
+  if (reverse) {
+    ids.reverse();
+  }
+  for (const id of ids) {
+    console.log(id.toString());
+  }
+  return 0;
+}
+
+function runDecode(hex: boolean): number {
+  let bytes = fs.readFileSync(0);
+  if (hex) {
+    const text = bytes.toString("utf8").replace(/\s+/g, "");
+    if (!/^(?:[0-9a-fA-F]{2})*$/.test(text)) {
+      console.error("decode: input is not valid hex");
+      return 1;
+    }
+    bytes = Buffer.from(text, "hex");
+  }
+
+  if (bytes.length % 20 !== 0) {
+    console.error(
+      `decode: input is ${bytes.length} bytes, not a multiple of 20`
     );
+    return 1;
   }
 
-  console.log(result);
+  for (let offset = 0; offset < bytes.length; offset += 20) {
+    const ksuid = KSUID.fromBytes(bytes.subarray(offset, offset + 20));
+    console.log(ksuid.toString());
+  }
+  return 0;
+}
+
+function runStats(): number {
+  let total = 0;
+  let invalid = 0;
+  const distinct = new Set<string>();
+  let earliest = Infinity;
+  let latest = -Infinity;
+
+  for (const line of readStdinLines()) {
+    if (!isCanonical(line)) {
+      invalid++;
+      continue;
+    }
+    const timestamp = KSUID.parse(line).timestamp;
+    total++;
+    distinct.add(line);
+    earliest = Math.min(earliest, timestamp);
+    latest = Math.max(latest, timestamp);
+  }
+
+  console.log(`total:    ${total}`);
+  console.log(`distinct: ${distinct.size}`);
+  if (total > 0) {
+    const time = (timestamp: number): string =>
+      timestampToTime(timestamp).toISOString();
+    console.log(`earliest: ${time(earliest)}`);
+    console.log(`latest:   ${time(latest)}`);
+    console.log(`span:     ${latest - earliest}s`);
+  }
+  console.log(`invalid:  ${invalid}`);
+  return 0;
+}
+
+// Reports whether stdin is a pipe or a file, which can be read without
+// blocking on a terminal or an inherited descriptor nobody writes to.
+function stdinIsPiped(): boolean {
+  try {
+    const stats = fs.fstatSync(0);
+    return stats.isFIFO() || stats.isFile();
+  } catch {
+    return false;
+  }
+}
+
+function runInspect(
+  lines: string[],
+  printFunction: (ksuid: KSUID) => void,
+  verbose: boolean,
+  strict: boolean
+): number {
+  let status = 0;
+  for (let i = 0; i < lines.length; i++) {
+    const line = lines[i].trim();
+    if (line === "") {
+      continue;
+    }
+
+    let ksuid: KSUID;
+    try {
+      ksuid = KSUID.parseAny(line);
+    } catch {
+      console.error(`line ${i + 1}: invalid KSUID "${line}"`);
+      if (strict) {
+        return 1;
+      }
+      status = 1;
+      continue;
+    }
+
+    if (verbose) {
+      process.stdout.write(`${ksuid.toString()}: `);
+    }
+    printFunction(ksuid);
+  }
+  return status;
+}
+
+const RFC3339_PATTERN =
+  /^\d{4}-\d{2}-\d{2}T\d{2}:\d{2}:\d{2}(\.\d+)?(Z|[+-]\d{2}:\d{2})$/i;
+
+// Converts a --timestamp value, Unix seconds or an RFC 3339 time, to a KSUID
+// timestamp. Returns null if the value is malformed or out of range.
+function parseTimestampFlag(value: string): number | null {
+  let seconds: number;
+  if (/^\d+$/.test(value)) {
+    seconds = Number(value);
+  } else if (RFC3339_PATTERN.test(value)) {
+    seconds = Math.floor(Date.parse(value) / 1000);
+  } else {
+    return null;
+  }
+
+  const timestamp = seconds - EPOCH;
+  if (!Number.isSafeInteger(timestamp) || timestamp < 0) {
+    return null;
+  }
+  return timestamp > 0xffffffff ? null : timestamp;
 }
 
 function main(): void {
   const args = parseArgs(process.argv);
 
+  if (args.args[0] === "validate") {
+    process.exit(runValidate(args.args.slice(1)));
+  }
+  if (args.args[0] === "verify") {
+    process.exit(runVerify(args.args.slice(1), args.quiet));
+  }
+  if (args.args[0] === "decode") {
+    process.exitCode = runDecode(args.hex);
+    return;
+  }
+  if (args.args[0] === "stats") {
+    process.exitCode = runStats();
+    return;
+  }
+  if (args.args[0] === "sort") {
+    // Let piped output drain instead of cutting it off with process.exit()
+    process.exitCode = runSort(
+      args.reverse,
+      args.skipInvalid,
+      args.unique,
+      args.verbose
+    );
+    return;
+  }
+
   let printFunction: (ksuid: KSUID) => void;
 
   switch (args.format) {
@@ -151,6 +586,9 @@ function main(): void {
     case "inspect":
       printFunction = printInspect;
       break;
+    case "json":
+      printFunction = printJSON;
+      break;
     case "time":
       printFunction = printTime;
       break;
@@ -163,6 +601,12 @@ function main(): void {
     case "raw":
       printFunction = printRaw;
       break;
+    case "hex":
+      printFunction = printHex;
+      break;
+    case "base64":
+      printFunction = printBase64;
+      break;
     case "template":
       printFunction = (ksuid: KSUID) => printTemplate(ksuid, args.template);
       break;
@@ -171,18 +615,70 @@ function main(): void {
       process.exit(1);
   }
 
+  // Inspecting with nothing to inspect reads KSUIDs from a pipe, a file or
+  // an explicit - instead of generating them, so extracted IDs can be fed
+  // straight in. An empty pipe still generates, as with no stdin at all.
+  if (
+    args.format === "inspect" &&
+    args.args.length === 0 &&
+    !args.countSet &&
+    !args.timestamp &&
+    !args.payload &&
+    (args.stdin || stdinIsPiped())
+  ) {
+    const lines = fs.readFileSync(0, "utf8").split(/\r?\n/);
+    if (args.stdin || lines.some(line => line.trim() !== "")) {
+      process.exitCode = runInspect(
+        lines,
+        printFunction,
+        args.verbose,
+        args.strict
+      );
+      return;
+    }
+  }
+
+  let timestamp: number | null = null;
+  if (args.timestamp) {
+    timestamp = parseTimestampFlag(args.timestamp);
+    if (timestamp === null) {
+      console.error(
+        `Invalid --timestamp "${args.timestamp}": expected Unix seconds or an RFC 3339 time between ${timestampToTime(0).toISOString()} and ${timestampToTime(0xffffffff).toISOString()}`
+      );
+      process.exit(1);
+    }
+  }
+
+  let payload: Buffer | null = null;
+  if (args.payload) {
+    if (!/^[0-9a-fA-F]{32}$/.test(args.payload)) {
+      console.error(
+        `Invalid --payload "${args.payload}": expected 32 hex characters`
+      );
+      process.exit(1);
+    }
+    payload = Buffer.from(args.payload, "hex");
+  }
+
   // If no KSUIDs provided, generate new ones
   const ksuids: string[] = args.args;
   if (ksuids.length === 0) {
     for (let i = 0; i < args.count; i++) {
-      ksuids.push(KSUID.random().toString());
+      const ksuid =
+        timestamp === null && payload === null
+          ? KSUID.random()
+          : KSUID.fromParts(
+              timestamp ?? Math.floor(Date.now() / 1000) - EPOCH,
+              payload ?? crypto.randomBytes(16)
+            );
+      ksuids.push(ksuid.toString());
     }
   }
 
   // Parse and process each KSUID
   for (const ksuidString of ksuids) {
     try {
-      const ksuid = KSUID.parse(ksuidString);
+      const ksuid = KSUID.parseAny(ksuidString);
 
       if (args.verbose) {
         process.stdout.write(`${ksuid.toString()}: `);
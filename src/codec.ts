@@ -0,0 +1,201 @@
+import { Buffer } from "buffer";
+import type { Readable } from "stream";
+import { KSUID } from "./ksuid";
+import { BASE62_ALPHABET } from "./base62";
+import { KSUIDError, KSUID_ERROR_CODES } from "./errors";
+
+const TIMESTAMP_LENGTH = 4;
+const PAYLOAD_LENGTH = 16;
+const KSUID_LENGTH = TIMESTAMP_LENGTH + PAYLOAD_LENGTH;
+const BASE = 62n;
+
+// Number of base62 characters needed to hold n KSUIDs' worth of bytes.
+function packedLength(n: number): number {
+  const limit = 1n << BigInt(KSUID_LENGTH * 8 * n);
+  let length = 0;
+  for (let capacity = 1n; capacity < limit; capacity *= BASE) {
+    length++;
+  }
+  return length;
+}
+
+/**
+ * Splits a batch into a timestamp column (4 bytes per KSUID) and a payload
+ * column (16 bytes per KSUID). Keeping the highly repetitive timestamps apart
+ * from the random payloads lets general-purpose compressors do much better on
+ * the timestamp column.
+ */
+export function encodeColumnar(ids: KSUID[]): {
+  timestamps: Buffer;
+  payloads: Buffer;
+} {
+  const timestamps = Buffer.alloc(ids.length * TIMESTAMP_LENGTH);
+  const payloads = Buffer.alloc(ids.length * PAYLOAD_LENGTH);
+
+  for (let i = 0; i < ids.length; i++) {
+    const buffer = ids[i].toBuffer();
+    buffer.copy(timestamps, i * TIMESTAMP_LENGTH, 0, TIMESTAMP_LENGTH);
+    buffer.copy(payloads, i * PAYLOAD_LENGTH, TIMESTAMP_LENGTH);
+  }
+
+  return { timestamps, payloads };
+}
+
+/**
+ * Reassembles a batch from the columns produced by encodeColumnar(). Throws if
+ * either column has a partial entry or the columns hold different counts.
+ */
+export function decodeColumnar(timestamps: Buffer, payloads: Buffer): KSUID[] {
+  if (timestamps == null) {
+    throw KSUIDError.invalidInput(timestamps, "timestamps");
+  }
+  if (payloads == null) {
+    throw KSUIDError.invalidInput(payloads, "payloads");
+  }
+
+  const count = timestamps.length / TIMESTAMP_LENGTH;
+  if (
+    timestamps.length % TIMESTAMP_LENGTH !== 0 ||
+    payloads.length % PAYLOAD_LENGTH !== 0 ||
+    payloads.length / PAYLOAD_LENGTH !== count
+  ) {
+    throw KSUIDError.malformedData(
+      `column lengths ${timestamps.length} and ${payloads.length} do not describe the same number of KSUIDs`
+    );
+  }
+
+  const ids: KSUID[] = [];
+  for (let i = 0; i < count; i++) {
+    ids.push(
+      KSUID.fromParts(
+        timestamps.readUInt32BE(i * TIMESTAMP_LENGTH),
+        payloads.subarray(i * PAYLOAD_LENGTH, (i + 1) * PAYLOAD_LENGTH)
+      )
+    );
+  }
+  return ids;
+}
+
+/**
+ * Encodes several KSUIDs as one opaque base62 token by treating their
+ * concatenated raw bytes as a single big-endian number. The token is
+ * zero-padded to a fixed length for the number of KSUIDs, which grows with the
+ * count: 27 characters for one KSUID, 54 for two and 81 for three. A single
+ * KSUID packs to its usual string form.
+ */
+export function packKSUIDs(...ids: KSUID[]): string {
+  const bytes = Buffer.concat(ids.map(id => id.toBuffer()));
+  let num = bytes.length > 0 ? BigInt("0x" + bytes.toString("hex")) : 0n;
+
+  let encoded = "";
+  while (num > 0n) {
+    encoded = BASE62_ALPHABET[Number(num % BASE)] + encoded;
+    num /= BASE;
+  }
+  return encoded.padStart(packedLength(ids.length), "0");
+}
+
+/**
+ * Decodes a token produced by packKSUIDs() back into its n KSUIDs. Throws if
+ * the token has the wrong length for n KSUIDs, contains a character outside
+ * the base62 alphabet, or decodes to more than 20 * n bytes.
+ */
+export function unpackKSUIDs(s: string, n: number): KSUID[] {
+  if (s == null) {
+    throw KSUIDError.invalidInput(s, "string");
+  }
+  if (!Number.isInteger(n) || n < 0) {
+    throw new KSUIDError(
+      `Invalid KSUID count: must be a non-negative integer, got ${n}`,
+      KSUID_ERROR_CODES.INVALID_INPUT,
+      { input: n, expected: "non-negative integer", actual: String(n) }
+    );
+  }
+
+  const length = packedLength(n);
+  if (s.length !== length) {
+    throw KSUIDError.invalidStringLength(s, length);
+  }
+
+  let num = 0n;
+  for (let i = 0; i < s.length; i++) {
+    const value = BASE62_ALPHABET.indexOf(s[i]);
+    if (value < 0) {
+      throw KSUIDError.invalidCharacter(s[i], i);
+    }
+    num = num * BASE + BigInt(value);
+  }
+
+  const hex = num.toString(16).padStart(KSUID_LENGTH * 2 * n, "0");
+  if (hex.length !== KSUID_LENGTH * 2 * n) {
+    throw KSUIDError.malformedData(
+      `packed value exceeds ${KSUID_LENGTH * n} bytes for ${n} KSUIDs`
+    );
+  }
+
+  const bytes = Buffer.from(hex, "hex");
+  const ids: KSUID[] = [];
+  for (let i = 0; i < n; i++) {
+    ids.push(
+      KSUID.fromBytes(bytes.subarray(i * KSUID_LENGTH, (i + 1) * KSUID_LENGTH))
+    );
+  }
+  return ids;
+}
+
+/**
+ * Reads exactly 20 raw bytes from stream, as written by KSUID.writeTo(), and
+ * decodes them. Resolves to null when the stream ends cleanly before the next
+ * KSUID, and rejects with MALFORMED_DATA when it ends partway through one.
+ * Only bytes belonging to the returned KSUID are consumed, so it can be
+ * called in a loop to read a stream of KSUIDs.
+ */
+export function readKSUID(stream: Readable): Promise<KSUID | null> {
+  return new Promise((resolve, reject) => {
+    if (stream.readableEnded) {
+      resolve(null);
+      return;
+    }
+
+    const cleanup = (): void => {
+      stream.off("readable", onReadable);
+      stream.off("end", onEnd);
+      stream.off("error", onError);
+    };
+    const onReadable = (): void => {
+      const chunk: Buffer | null = stream.read(KSUID_LENGTH);
+      if (chunk === null) {
+        return;
+      }
+      cleanup();
+      if (chunk.length < KSUID_LENGTH) {
+        reject(
+          new KSUIDError(
+            `Unexpected end of stream: expected ${KSUID_LENGTH} bytes, got ${chunk.length}`,
+            KSUID_ERROR_CODES.MALFORMED_DATA,
+            {
+              input: chunk,
+              expected: `${KSUID_LENGTH} bytes`,
+              actual: `${chunk.length} bytes`,
+            }
+          )
+        );
+        return;
+      }
+      resolve(KSUID.fromBytes(chunk));
+    };
+    const onEnd = (): void => {
+      cleanup();
+      resolve(null);
+    };
+    const onError = (error: Error): void => {
+      cleanup();
+      reject(error);
+    };
+
+    stream.on("readable", onReadable);
+    stream.on("end", onEnd);
+    stream.on("error", onError);
+    onReadable();
+  });
+}
@@ -124,9 +124,7 @@ export class CompressedSet {
         pos += deltaLength;
 
         // Copy payload
-        const payload = id.payload;
-        payload.copy(buffer, pos);
-        pos += 16;
+        pos = id.payloadInto(buffer, pos);
 
         timestamp = t;
       } else {
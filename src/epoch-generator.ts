@@ -0,0 +1,67 @@
+import * as crypto from "crypto";
+import { KSUID } from "./ksuid";
+import { KSUIDError, KSUID_ERROR_CODES } from "./errors";
+
+/**
+ * EpochGenerator produces KSUIDs whose timestamps count seconds from a custom
+ * epoch instead of the standard 2014-05-13T16:53:20Z, for private ID schemes
+ * that want the 136 year range to start at, say, a product launch.
+ *
+ * ```typescript
+ * const gen = new EpochGenerator({ epoch: new Date("2024-01-01T00:00:00Z") });
+ * const id = gen.random();
+ * gen.time(id); // creation time, read relative to the custom epoch
+ * ```
+ *
+ * WARNING: nothing in a KSUID records which epoch it was built with. To every
+ * other reader, including KSUID methods such as timeRange(), the CLI and
+ * other KSUID implementations, these KSUIDs look like standard ones created
+ * years earlier. Only this generator's time() interprets them correctly. Never
+ * store them alongside standard KSUIDs or across epochs: they would sort by
+ * their raw timestamps, not by when they were created.
+ */
+export class EpochGenerator {
+  private readonly epoch: number;
+
+  constructor(options: { epoch: Date }) {
+    const millis = options.epoch?.getTime();
+    if (!Number.isFinite(millis)) {
+      throw new KSUIDError(
+        `Invalid epoch: must be a valid Date, got ${String(options.epoch)}`,
+        KSUID_ERROR_CODES.INVALID_INPUT,
+        {
+          input: options.epoch,
+          expected: "valid Date",
+          actual: String(options.epoch),
+        }
+      );
+    }
+    this.epoch = Math.floor(millis / 1000);
+  }
+
+  /**
+   * Generates a random KSUID for the current time, counted from this
+   * generator's epoch. Throws if the current time is before the epoch.
+   */
+  random(): KSUID {
+    return this.fromParts(new Date(), crypto.randomBytes(16));
+  }
+
+  /**
+   * Builds a KSUID for the second containing t, counted from this generator's
+   * epoch, with the given 16-byte payload. Throws if t is before the epoch or
+   * more than 2^32 - 1 seconds after it.
+   */
+  fromParts(t: Date, payload: Buffer): KSUID {
+    const timestamp = Math.floor(t.getTime() / 1000) - this.epoch;
+    return KSUID.fromParts(timestamp, payload);
+  }
+
+  /**
+   * Returns the creation time of a KSUID made by this generator, interpreting
+   * its timestamp relative to this generator's epoch.
+   */
+  time(k: KSUID): Date {
+    return new Date((k.timestamp + this.epoch) * 1000);
+  }
+}
@@ -13,6 +13,10 @@ export const KSUID_ERROR_CODES = {
   MALFORMED_DATA: "MALFORMED_DATA",
   CORRUPTION_DETECTED: "CORRUPTION_DETECTED",
 
+  // Time window errors
+  TIMESTAMP_EXPIRED: "TIMESTAMP_EXPIRED",
+  TIMESTAMP_IN_FUTURE: "TIMESTAMP_IN_FUTURE",
+
   // Operation errors
   SEQUENCE_EXHAUSTED: "SEQUENCE_EXHAUSTED",
   OPERATION_FAILED: "OPERATION_FAILED",
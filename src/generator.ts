@@ -0,0 +1,43 @@
+import { KSUID } from "./ksuid";
+import { KSUIDError, KSUID_ERROR_CODES } from "./errors";
+
+/**
+ * KSUIDGenerator is anything that produces KSUIDs one at a time, returning
+ * null once it is exhausted. Sequence implements it.
+ */
+export interface KSUIDGenerator {
+  next(): KSUID | null;
+}
+
+/**
+ * Draws n KSUIDs from the generator and throws at the first one that is not
+ * strictly greater than its predecessor, reporting the index and both values.
+ * Also throws if the generator is exhausted before producing n KSUIDs. Meant
+ * for validating generator implementations in tests.
+ */
+export function assertMonotonic(g: KSUIDGenerator, n: number): void {
+  let previous: KSUID | null = null;
+  for (let i = 0; i < n; i++) {
+    const current = g.next();
+    if (current === null) {
+      throw new KSUIDError(
+        `Generator exhausted after ${i} of ${n} KSUIDs`,
+        KSUID_ERROR_CODES.OPERATION_FAILED,
+        { expected: `${n} KSUIDs`, actual: `${i} KSUIDs` }
+      );
+    }
+
+    if (previous !== null && previous.compare(current) >= 0) {
+      throw new KSUIDError(
+        `Generator is not monotonic at index ${i}: ${current.toString()} does not follow ${previous.toString()}`,
+        KSUID_ERROR_CODES.OPERATION_FAILED,
+        {
+          input: i,
+          expected: `KSUID greater than ${previous.toString()}`,
+          actual: current.toString(),
+        }
+      );
+    }
+    previous = current;
+  }
+}
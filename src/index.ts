@@ -1,8 +1,56 @@
-export { KSUID } from "./ksuid";
-export { Base62 } from "./base62";
+export { KSUID, KSUID_STRING_PATTERN, EPOCH } from "./ksuid";
+export { Base62, BASE62_ALPHABET } from "./base62";
 export { Uint128 } from "./uint128";
 export { Sequence } from "./sequence";
-export { sort, isSorted, compare } from "./sort";
+export { BoundedSequence } from "./bounded-sequence";
+export { MonotonicGenerator, stream } from "./monotonic";
+export { EpochGenerator } from "./epoch-generator";
+export {
+  sort,
+  isSorted,
+  compare,
+  compareTime,
+  compareSuffix,
+  merge,
+  equal,
+} from "./sort";
+export {
+  interpolate,
+  estimateCount,
+  commonPrefixBits,
+  nthInRange,
+  between,
+  distance,
+  range,
+} from "./range";
+export {
+  validateBatch,
+  percentile,
+  countPerSecond,
+  findDuplicateSeconds,
+  longestRun,
+  randomBatch,
+  shuffledIndexedBatch,
+  backfillBatch,
+  generateBetween,
+  batchesOverlap,
+} from "./batch";
+export { composeKey, decomposeKey, truncationCollisions } from "./key";
+export {
+  encodeColumnar,
+  decodeColumnar,
+  packKSUIDs,
+  unpackKSUIDs,
+  readKSUID,
+} from "./codec";
+export {
+  overlapDuration,
+  timeToTimestamp,
+  timestampToTime,
+} from "./time";
+export { assertMonotonic } from "./generator";
+export type { KSUIDGenerator } from "./generator";
+export type { RandomSource } from "./ksuid";
 export { CompressedSet, CompressedSetIter } from "./compressed-set";
 export { KSUIDError, isKSUIDError, KSUID_ERROR_CODES } from "./errors";
 export type { KSUIDErrorCode } from "./errors";
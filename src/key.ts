@@ -0,0 +1,76 @@
+import { Buffer } from "buffer";
+import { KSUID } from "./ksuid";
+import { KSUIDError, KSUID_ERROR_CODES } from "./errors";
+
+const KSUID_BYTE_LENGTH = 20;
+
+/**
+ * Returns a 40-byte composite key made of the parent's raw bytes followed by
+ * the child's. Since each half sorts correctly byte-wise, comparing composite
+ * keys with Buffer.compare orders them by parent first and then by child.
+ */
+export function composeKey(parent: KSUID, child: KSUID): Buffer {
+  return Buffer.concat([parent.toBuffer(), child.toBuffer()]);
+}
+
+/**
+ * Splits a 40-byte composite key produced by composeKey() back into its
+ * parent and child KSUIDs.
+ */
+export function decomposeKey(key: Buffer): { parent: KSUID; child: KSUID } {
+  if (key == null) {
+    throw KSUIDError.invalidInput(key, "key");
+  }
+
+  if (key.length !== KSUID_BYTE_LENGTH * 2) {
+    throw KSUIDError.invalidBufferLength(
+      key,
+      KSUID_BYTE_LENGTH * 2,
+      "composite key"
+    );
+  }
+
+  return {
+    parent: KSUID.fromBytes(key.subarray(0, KSUID_BYTE_LENGTH)),
+    child: KSUID.fromBytes(key.subarray(KSUID_BYTE_LENGTH)),
+  };
+}
+
+/**
+ * Returns the index pairs [i, j] (i < j) of KSUIDs whose first keyLen bytes
+ * are identical, i.e. that would collide if truncated to keyLen bytes, as
+ * key96() does with 12. Pairs are ordered by i, then j. Running this over a
+ * representative batch shows whether a shorter key is safe for the data.
+ */
+export function truncationCollisions(
+  ids: KSUID[],
+  keyLen: number
+): [number, number][] {
+  if (!Number.isInteger(keyLen) || keyLen < 1 || keyLen > KSUID_BYTE_LENGTH) {
+    throw new KSUIDError(
+      `Invalid key length: must be 1 to ${KSUID_BYTE_LENGTH}, got ${keyLen}`,
+      KSUID_ERROR_CODES.INVALID_INPUT,
+      {
+        input: keyLen,
+        expected: `1 to ${KSUID_BYTE_LENGTH}`,
+        actual: String(keyLen),
+      }
+    );
+  }
+
+  const groups = new Map<string, number[]>();
+  const pairs: [number, number][] = [];
+  for (let j = 0; j < ids.length; j++) {
+    const key = ids[j].toBuffer().toString("hex", 0, keyLen);
+    const group = groups.get(key);
+    if (group === undefined) {
+      groups.set(key, [j]);
+      continue;
+    }
+    for (const i of group) {
+      pairs.push([i, j]);
+    }
+    group.push(j);
+  }
+  return pairs.sort((a, b) => a[0] - b[0] || a[1] - b[1]);
+}
@@ -2,12 +2,173 @@ import { Buffer } from "buffer";
 import * as crypto from "crypto";
 import { Base62 } from "./base62";
 import { Uint128 } from "./uint128";
-import { KSUIDError } from "./errors";
+import { KSUIDError, KSUID_ERROR_CODES, isKSUIDError } from "./errors";
 
-const EPOCH = 1400000000; // 2014-05-13T16:53:20Z
+/**
+ * The KSUID epoch in Unix seconds, 2014-05-13T16:53:20Z. A KSUID's timestamp
+ * counts seconds from this instant. It is exported as a number rather than a
+ * shared, mutable Date; new Date(EPOCH * 1000) gives the instant itself.
+ */
+export const EPOCH = 1400000000;
+
+/**
+ * Regular expression source matching the 27 character base62 string form, for
+ * embedding in JSON Schema or OpenAPI "pattern" fields. It checks the alphabet
+ * and length only; strings encoding values above the maximum KSUID also match.
+ */
+export const KSUID_STRING_PATTERN = "^[0-9A-Za-z]{27}$";
+
+/**
+ * A source of random bytes with the signature of crypto.randomBytes(): it
+ * returns size bytes or throws.
+ */
+export type RandomSource = (size: number) => Buffer;
 const TIMESTAMP_LENGTH = 4;
 const PAYLOAD_LENGTH = 16;
 const KSUID_LENGTH = TIMESTAMP_LENGTH + PAYLOAD_LENGTH;
+const STRING_LENGTH = 27;
+const BASE64_LENGTH = 28;
+const MAX_STRING = "aWgEPTl1tmebfsQzFP4bxwgy80V";
+const CURSOR_VERSION = "1";
+// Digits and uppercase letters without the easily confused 0/O and 1/I/L.
+// Its length of 31 is prime, so the weighted check character catches any
+// single substituted character.
+const SPOKEN_ALPHABET = "23456789ABCDEFGHJKMNPQRSTUVWXYZ";
+const FNV_OFFSET_BASIS = 0xcbf29ce484222325n;
+const FNV_PRIME = 0x100000001b3n;
+// Rounds of the Feistel network used by obfuscate(). Four rounds already give
+// a strong pseudorandom permutation; eight leave a margin.
+const FEISTEL_ROUNDS = 8;
+const FEISTEL_HALF = KSUID_LENGTH / 2;
+const UUID_PATTERN =
+  /^[0-9a-f]{8}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{12}$/i;
+
+// Payload source for KSUID.random(), replaceable with KSUID.setRand().
+let randomSource: RandomSource = crypto.randomBytes;
+
+// 64-bit FNV-1a hash, used where a stable, non-cryptographic hash is needed.
+function fnv1a64(data: Buffer): bigint {
+  let hash = FNV_OFFSET_BASIS;
+  for (const byte of data) {
+    hash ^= BigInt(byte);
+    hash = (hash * FNV_PRIME) & 0xffffffffffffffffn;
+  }
+  return hash;
+}
+
+// CRC-8 with polynomial 0x07, used to make checksummed payloads.
+function crc8(data: Buffer): number {
+  let crc = 0;
+  for (const byte of data) {
+    crc ^= byte;
+    for (let bit = 0; bit < 8; bit++) {
+      crc = crc & 0x80 ? ((crc << 1) ^ 0x07) & 0xff : (crc << 1) & 0xff;
+    }
+  }
+  return crc;
+}
+
+// Balanced Feistel network over the 20 bytes, with HMAC-SHA256 of the round
+// number and one half as the round function. Running it with decrypt set
+// applies the rounds in reverse and undoes the permutation.
+function feistel(data: Buffer, key: Buffer, decrypt: boolean): Buffer {
+  let left = Buffer.from(data.subarray(0, FEISTEL_HALF));
+  let right = Buffer.from(data.subarray(FEISTEL_HALF));
+
+  const round = (n: number, half: Buffer): Buffer =>
+    crypto
+      .createHmac("sha256", key)
+      .update(Buffer.from([n]))
+      .update(half)
+      .digest()
+      .subarray(0, FEISTEL_HALF);
+  const xor = (a: Buffer, b: Buffer): Buffer =>
+    Buffer.from(a.map((byte, i) => byte ^ b[i]));
+
+  for (let i = 0; i < FEISTEL_ROUNDS; i++) {
+    if (decrypt) {
+      [left, right] = [xor(right, round(FEISTEL_ROUNDS - 1 - i, left)), left];
+    } else {
+      [left, right] = [right, xor(left, round(i, right))];
+    }
+  }
+  return Buffer.concat([left, right]);
+}
+
+function checkObfuscationKey(key: Buffer): void {
+  if (key == null || key.length === 0) {
+    throw new KSUIDError(
+      "Invalid obfuscation key: must not be empty",
+      KSUID_ERROR_CODES.INVALID_INPUT,
+      { input: key, expected: "non-empty key", actual: String(key?.length) }
+    );
+  }
+}
+
+function checkAppendRoom(dst: Buffer, offset: number, size: number): void {
+  if (!Number.isInteger(offset) || offset < 0 || offset + size > dst.length) {
+    throw new KSUIDError(
+      `Invalid destination: need ${size} bytes at offset ${offset}, buffer has ${dst.length}`,
+      KSUID_ERROR_CODES.INVALID_BUFFER_SIZE,
+      {
+        input: offset,
+        expected: `${size} bytes available at offset ${offset}`,
+        actual: `${dst.length} byte buffer`,
+      }
+    );
+  }
+}
+
+function checkStepCount(n: bigint): bigint {
+  if (typeof n !== "bigint" || n < 0n || n > 0xffffffffffffffffn) {
+    throw new KSUIDError(
+      `Invalid step count: must be a uint64 bigint, got ${String(n)}`,
+      KSUID_ERROR_CODES.INVALID_INPUT,
+      { input: n, expected: "uint64 bigint", actual: String(n) }
+    );
+  }
+  return n;
+}
+
+/**
+ * Interprets the 20 bytes of a KSUID as an unsigned 160-bit big-endian
+ * integer. The timestamp occupies the most significant 32 bits, so the
+ * numeric order matches the ordering of compare().
+ */
+export function ksuidToBigInt(k: KSUID): bigint {
+  return BigInt("0x" + k.toBuffer().toString("hex"));
+}
+
+/**
+ * Builds a KSUID from an unsigned 160-bit integer. The value must be in the
+ * range [0, 2^160).
+ */
+export function bigIntToKSUID(n: bigint): KSUID {
+  const hex = n.toString(16).padStart(KSUID_LENGTH * 2, "0");
+  return KSUID.fromBytes(Buffer.from(hex, "hex"));
+}
+
+/**
+ * Converts t to a KSUID timestamp: whole seconds since EPOCH, rounded down.
+ * Times before the KSUID epoch clamp to 0 and times past the last
+ * representable second (2150-06-19T23:21:35Z) clamp to 0xFFFFFFFF, the same
+ * rule KSUID.minForTime() and KSUID.maxForTime() apply.
+ */
+export function timeToTimestamp(t: Date): number {
+  const timestamp = Math.floor(t.getTime() / 1000) - EPOCH;
+  return Math.min(Math.max(timestamp, 0), 0xffffffff);
+}
+
+/**
+ * Converts a KSUID timestamp back to the Date at the start of its second.
+ * Unlike timeToTimestamp() nothing is clamped: ts must be a uint32.
+ */
+export function timestampToTime(ts: number): Date {
+  if (!Number.isInteger(ts) || ts < 0 || ts > 0xffffffff) {
+    throw KSUIDError.invalidTimestamp(ts);
+  }
+  return new Date((ts + EPOCH) * 1000);
+}
 
 export class KSUID {
   private constructor(private readonly buffer: Buffer) {
@@ -17,8 +178,65 @@ export class KSUID {
   }
 
   static random(): KSUID {
+    return KSUID.randomWithReader(randomSource);
+  }
+
+  /**
+   * Generates a KSUID for the current time whose payload is read from the
+   * given source instead of the default one. Errors thrown by the source are
+   * propagated, and a source returning other than 16 bytes is rejected.
+   */
+  static randomWithReader(source: RandomSource): KSUID {
+    const now = Math.floor(Date.now() / 1000 - EPOCH);
+    const payload = source(PAYLOAD_LENGTH);
+    return KSUID.fromParts(now, payload);
+  }
+
+  /**
+   * Replaces the random source used by KSUID.random() for the whole process,
+   * for example with a seeded generator to get reproducible output in tests.
+   * Passing null restores the default, crypto.randomBytes(). Other generation
+   * methods are unaffected.
+   */
+  static setRand(source: RandomSource | null): void {
+    randomSource = source ?? crypto.randomBytes;
+  }
+
+  /**
+   * Generates a random KSUID whose payload reserves its top 4 bits for a
+   * version indicator in the range 0..15, readable back with version().
+   *
+   * Reserving the nibble leaves 124 random bits in the payload instead of 128,
+   * which slightly raises the (still negligible) chance of a collision.
+   */
+  static randomVersioned(version: number): KSUID {
+    if (!Number.isInteger(version) || version < 0 || version > 15) {
+      throw new KSUIDError(
+        `Invalid version: must be 0 to 15, got ${version}`,
+        KSUID_ERROR_CODES.INVALID_INPUT,
+        { input: version, expected: "0 to 15", actual: String(version) }
+      );
+    }
+
     const now = Math.floor(Date.now() / 1000 - EPOCH);
     const payload = crypto.randomBytes(PAYLOAD_LENGTH);
+    payload[0] = (version << 4) | (payload[0] & 0x0f);
+    return KSUID.fromParts(now, payload);
+  }
+
+  /**
+   * Generates a random KSUID whose last payload byte is a CRC-8 checksum of
+   * the preceding 15 payload bytes, so corruption of the payload (including
+   * any single-bit error) can be detected with verifyChecksum().
+   *
+   * The checksum byte leaves 120 random bits in the payload instead of 128.
+   * KSUIDs from KSUID.random() are not checksummed and pass verifyChecksum()
+   * only by chance (about 1 in 256).
+   */
+  static randomChecked(): KSUID {
+    const now = Math.floor(Date.now() / 1000 - EPOCH);
+    const payload = crypto.randomBytes(PAYLOAD_LENGTH);
+    payload[PAYLOAD_LENGTH - 1] = crc8(payload.subarray(0, PAYLOAD_LENGTH - 1));
     return KSUID.fromParts(now, payload);
   }
 
@@ -52,6 +270,46 @@ export class KSUID {
     return new KSUID(buffer);
   }
 
+  /**
+   * Builds a KSUID from a canonical 8-4-4-4-12 UUID string, used as the
+   * 16-byte payload, and the second of the given time. This is the inverse of
+   * payloadUUID().
+   */
+  static fromPayloadUUID(u: string, t: Date): KSUID {
+    if (u == null) {
+      throw KSUIDError.invalidInput(u, "UUID");
+    }
+
+    if (!UUID_PATTERN.test(u)) {
+      throw new KSUIDError(
+        `Invalid UUID: expected 8-4-4-4-12 hex format, got "${u}"`,
+        KSUID_ERROR_CODES.INVALID_INPUT,
+        { input: u, expected: "8-4-4-4-12 hex format", actual: u }
+      );
+    }
+
+    const timestamp = Math.floor(t.getTime() / 1000) - EPOCH;
+    return KSUID.fromParts(timestamp, Buffer.from(u.replace(/-/g, ""), "hex"));
+  }
+
+  /**
+   * Builds a KSUID from the 16 bytes of a UUID, used as the payload, and the
+   * second of the given time. A UUID carries no KSUID timestamp, so it must be
+   * supplied; it defaults to the current time. This is the inverse of
+   * toUUID() when the original KSUID's time is passed.
+   */
+  static fromUUID(u: Buffer, t: Date = new Date()): KSUID {
+    if (u == null) {
+      throw KSUIDError.invalidInput(u, "UUID");
+    }
+    if (u.length !== PAYLOAD_LENGTH) {
+      throw KSUIDError.invalidBufferLength(u, PAYLOAD_LENGTH, "UUID");
+    }
+
+    const timestamp = Math.floor(t.getTime() / 1000) - EPOCH;
+    return KSUID.fromParts(timestamp, u);
+  }
+
   static parse(s: string): KSUID {
     if (s == null) {
       throw KSUIDError.invalidInput(s, "string");
@@ -65,6 +323,378 @@ export class KSUID {
     return new KSUID(buffer);
   }
 
+  /**
+   * Parses a KSUID from its base62, base64 or hex representation. The format
+   * is detected purely by length: 27 characters are decoded as base62, 28 as
+   * the standard padded base64 encoding of the 20 raw bytes and 40 as their
+   * hex encoding (either case). Any other length is rejected.
+   *
+   * Because detection only looks at length, a value in some other encoding
+   * that happens to be one of these lengths is not recognised as such and
+   * will either fail with an invalid character error or decode as the
+   * detected format.
+   */
+  static parseAny(s: string): KSUID {
+    if (s == null) {
+      throw KSUIDError.invalidInput(s, "string");
+    }
+
+    if (s.length === 27) {
+      return KSUID.parse(s);
+    }
+
+    if (s.length === KSUID_LENGTH * 2) {
+      const invalid = s.search(/[^0-9a-fA-F]/);
+      if (invalid !== -1) {
+        throw KSUIDError.invalidCharacter(s[invalid], invalid);
+      }
+      return new KSUID(Buffer.from(s, "hex"));
+    }
+
+    if (s.length === BASE64_LENGTH) {
+      const invalid = s.search(/[^A-Za-z0-9+/]/);
+      if (invalid !== -1 && invalid < BASE64_LENGTH - 1) {
+        throw KSUIDError.invalidCharacter(s[invalid], invalid);
+      }
+      const buffer = Buffer.from(s, "base64");
+      if (buffer.length !== KSUID_LENGTH || buffer.toString("base64") !== s) {
+        throw KSUIDError.malformedData(`"${s}" is not canonical base64`);
+      }
+      return new KSUID(buffer);
+    }
+
+    throw new KSUIDError(
+      `Invalid KSUID string: expected 27 (base62), 28 (base64) or 40 (hex) characters, got ${s.length}`,
+      KSUID_ERROR_CODES.INVALID_LENGTH,
+      {
+        input: s,
+        expected: "27, 28 or 40 characters",
+        actual: `${s.length} characters`,
+      }
+    );
+  }
+
+  /**
+   * Decodes a pagination cursor produced by cursor(). The returned KSUID is
+   * the last item of the previous page, so the next page is everything
+   * greater than it.
+   */
+  static parseCursor(s: string): KSUID {
+    if (s == null) {
+      throw KSUIDError.invalidInput(s, "cursor");
+    }
+
+    if (s.length !== 28) {
+      throw new KSUIDError(
+        `Invalid cursor: expected 28 characters, got ${s.length}`,
+        KSUID_ERROR_CODES.INVALID_LENGTH,
+        {
+          input: s,
+          expected: "28 characters",
+          actual: `${s.length} characters`,
+        }
+      );
+    }
+
+    if (s[0] !== CURSOR_VERSION) {
+      throw new KSUIDError(
+        `Invalid cursor: unsupported version '${s[0]}'`,
+        KSUID_ERROR_CODES.MALFORMED_DATA,
+        {
+          input: s,
+          expected: `version '${CURSOR_VERSION}'`,
+          actual: `version '${s[0]}'`,
+        }
+      );
+    }
+
+    return KSUID.parse(s.slice(1));
+  }
+
+  /**
+   * Parses a file name produced by filename(). Everything from the first dot
+   * onwards is treated as the extension and ignored, and the remaining stem
+   * must be a valid 27-character KSUID string.
+   */
+  static parseFilename(name: string): KSUID {
+    if (name == null) {
+      throw KSUIDError.invalidInput(name, "filename");
+    }
+
+    const dot = name.indexOf(".");
+    return KSUID.parse(dot === -1 ? name : name.slice(0, dot));
+  }
+
+  /**
+   * Returns the smallest KSUID for the second containing t, with an all-zero
+   * payload. Together with KSUID.maxForTime() it bounds a range scan over the
+   * KSUIDs generated in a time window: id >= minForTime(start) and
+   * id <= maxForTime(end).
+   *
+   * Times before the KSUID epoch clamp to timestamp 0 and times beyond the
+   * 32-bit range clamp to the maximum timestamp.
+   */
+  static minForTime(t: Date): KSUID {
+    return KSUID.fromParts(timeToTimestamp(t), Buffer.alloc(PAYLOAD_LENGTH));
+  }
+
+  /**
+   * Returns the largest KSUID for the second containing t, with an all-0xFF
+   * payload. Times are clamped as in KSUID.minForTime().
+   */
+  static maxForTime(t: Date): KSUID {
+    return KSUID.fromParts(
+      timeToTimestamp(t),
+      Buffer.alloc(PAYLOAD_LENGTH, 0xff)
+    );
+  }
+
+  /**
+   * Returns the inclusive bounds of every KSUID generated in the second
+   * containing t: KSUID.minForTime(t) and KSUID.maxForTime(t) together, ready
+   * for a range scan answering "which IDs were made in this second".
+   */
+  static rangeForTime(t: Date): { min: KSUID; max: KSUID } {
+    return { min: KSUID.minForTime(t), max: KSUID.maxForTime(t) };
+  }
+
+  /**
+   * Returns the smallest KSUID whose string form starts with prefix, i.e. the
+   * prefix padded with '0' characters. Together with KSUID.maxWithPrefix() it
+   * bounds a range scan over string keys sharing the prefix.
+   *
+   * The prefix must consist of base62 characters and be at most 27 long.
+   * Throws if no KSUID starts with the prefix, because the padded value would
+   * exceed the maximum KSUID.
+   */
+  static minWithPrefix(prefix: string): KSUID {
+    return KSUID.parse(KSUID.prefixBounds(prefix).min);
+  }
+
+  /**
+   * Returns the largest KSUID whose string form starts with prefix, i.e. the
+   * prefix padded with 'z' characters, clamped to the maximum KSUID. The same
+   * prefix rules as KSUID.minWithPrefix() apply.
+   */
+  static maxWithPrefix(prefix: string): KSUID {
+    return KSUID.parse(KSUID.prefixBounds(prefix).max);
+  }
+
+  private static prefixBounds(prefix: string): { min: string; max: string } {
+    if (prefix == null) {
+      throw KSUIDError.invalidInput(prefix, "prefix");
+    }
+
+    if (prefix.length > STRING_LENGTH) {
+      throw new KSUIDError(
+        `Invalid prefix: expected at most ${STRING_LENGTH} characters, got ${prefix.length}`,
+        KSUID_ERROR_CODES.INVALID_LENGTH,
+        {
+          input: prefix,
+          expected: `at most ${STRING_LENGTH} characters`,
+          actual: `${prefix.length} characters`,
+        }
+      );
+    }
+
+    const invalid = prefix.search(/[^0-9A-Za-z]/);
+    if (invalid !== -1) {
+      throw KSUIDError.invalidCharacter(prefix[invalid], invalid);
+    }
+
+    // The base62 alphabet is in ASCII order, so equal-length strings compare
+    // the same way as the values they encode.
+    const min = prefix.padEnd(STRING_LENGTH, "0");
+    if (min > MAX_STRING) {
+      throw new KSUIDError(
+        `Invalid prefix: no KSUID starts with "${prefix}"`,
+        KSUID_ERROR_CODES.INVALID_INPUT,
+        { input: prefix, expected: `prefix <= "${MAX_STRING}"`, actual: min }
+      );
+    }
+
+    const max = prefix.padEnd(STRING_LENGTH, "z");
+    return { min, max: max > MAX_STRING ? MAX_STRING : max };
+  }
+
+  /**
+   * Parses the 32-character base32hex form produced by toBase32Hex(). Letters
+   * are accepted in either case.
+   */
+  static parseBase32Hex(s: string): KSUID {
+    if (s == null) {
+      throw KSUIDError.invalidInput(s, "string");
+    }
+
+    if (s.length !== 32) {
+      throw KSUIDError.invalidStringLength(s, 32);
+    }
+
+    let n = 0n;
+    for (let i = 0; i < s.length; i++) {
+      const digit = parseInt(s[i], 32);
+      if (Number.isNaN(digit)) {
+        throw KSUIDError.invalidCharacter(s[i], i);
+      }
+      n = n * 32n + BigInt(digit);
+    }
+
+    return bigIntToKSUID(n);
+  }
+
+  /**
+   * Parses the UTF-8 text form produced by marshalText(), for serializers
+   * that exchange bytes rather than strings. Malformed input throws the same
+   * errors as KSUID.parse().
+   */
+  static unmarshalText(b: Buffer): KSUID {
+    if (b == null) {
+      throw KSUIDError.invalidInput(b, "buffer");
+    }
+    return KSUID.parse(Buffer.from(b).toString("utf8"));
+  }
+
+  /**
+   * Decodes the 20 raw bytes produced by marshalBinary(), for binary
+   * serializers such as v8.serialize() or message framing. Input of any other
+   * length throws, as in KSUID.fromBytes().
+   */
+  static unmarshalBinary(b: Buffer | Uint8Array): KSUID {
+    if (b == null) {
+      throw KSUIDError.invalidInput(b, "buffer");
+    }
+    return KSUID.fromBytes(Buffer.from(b));
+  }
+
+  /**
+   * Reads a KSUID from a value returned by a database driver: the 27
+   * character string (as a string or UTF-8 bytes), the 20 raw bytes, or null,
+   * which yields the nil KSUID. Any other type or byte length throws.
+   */
+  static scan(src: string | Buffer | Uint8Array | null | undefined): KSUID {
+    if (src == null) {
+      return KSUID.nil;
+    }
+    if (typeof src === "string") {
+      return KSUID.parse(src);
+    }
+    if (src instanceof Uint8Array) {
+      const buffer = Buffer.from(src);
+      if (buffer.length === KSUID_LENGTH) {
+        return KSUID.fromBytes(buffer);
+      }
+      if (buffer.length === STRING_LENGTH) {
+        return KSUID.parse(buffer.toString("utf8"));
+      }
+      throw new KSUIDError(
+        `Invalid scan source: expected ${KSUID_LENGTH} raw bytes or ${STRING_LENGTH} bytes of text, got ${buffer.length} bytes`,
+        KSUID_ERROR_CODES.INVALID_BUFFER_SIZE,
+        {
+          input: src,
+          expected: `${KSUID_LENGTH} or ${STRING_LENGTH} bytes`,
+          actual: `${buffer.length} bytes`,
+        }
+      );
+    }
+
+    throw new KSUIDError(
+      `Invalid scan source: unsupported type ${typeof src}`,
+      KSUID_ERROR_CODES.INVALID_INPUT,
+      {
+        input: src,
+        expected: "string, Buffer or null",
+        actual: typeof src,
+      }
+    );
+  }
+
+  /**
+   * Recovers the KSUID from a string produced by obfuscate() with the same
+   * key. Any canonical 27 character string decodes to some KSUID, so a wrong
+   * key yields a wrong KSUID rather than an error.
+   */
+  static deobfuscate(s: string, key: Buffer): KSUID {
+    checkObfuscationKey(key);
+    const buffer = KSUID.parse(KSUID.canonicalize(s)).buffer;
+    return new KSUID(feistel(buffer, key, true));
+  }
+
+  /**
+   * Converts a value decoded from JSON back into a KSUID, the inverse of
+   * toJSON(). A string is parsed, null yields the nil KSUID and any other JSON
+   * type throws. Use it from a JSON.parse() reviver or on a decoded field.
+   */
+  static fromJSON(value: unknown): KSUID {
+    if (value === null) {
+      return KSUID.nil;
+    }
+    if (typeof value !== "string") {
+      const actual = Array.isArray(value) ? "array" : typeof value;
+      throw new KSUIDError(
+        `Invalid JSON value: expected a string, got ${actual}`,
+        KSUID_ERROR_CODES.INVALID_INPUT,
+        { input: value, expected: "string or null", actual }
+      );
+    }
+    return KSUID.parse(value);
+  }
+
+  /**
+   * Reads a KSUID back from a JSON inspect object, such as a line printed by
+   * the CLI's json format. The "ksuid" field (or "string", for older output)
+   * is parsed, and when a "raw" hex field is also present it must describe
+   * the same 20 bytes, otherwise the object is reported as inconsistent.
+   */
+  static fromInspectJSON(data: string | Buffer): KSUID {
+    if (data == null) {
+      throw KSUIDError.invalidInput(data, "data");
+    }
+
+    let object: unknown;
+    try {
+      object = JSON.parse(data.toString());
+    } catch {
+      throw KSUIDError.malformedData("inspect output is not valid JSON");
+    }
+
+    if (object === null || typeof object !== "object") {
+      throw KSUIDError.malformedData("inspect output is not a JSON object");
+    }
+
+    const fields = object as {
+      ksuid?: unknown;
+      string?: unknown;
+      raw?: unknown;
+    };
+    const string = fields.ksuid ?? fields.string;
+    if (typeof string !== "string") {
+      throw KSUIDError.malformedData(
+        'inspect output has no "ksuid" or "string" field'
+      );
+    }
+
+    const raw = fields.raw;
+    const ksuid = KSUID.parse(string);
+    if (raw !== undefined) {
+      if (
+        typeof raw !== "string" ||
+        raw.toLowerCase() !== ksuid.buffer.toString("hex")
+      ) {
+        throw new KSUIDError(
+          'Inconsistent inspect output: "string" and "raw" fields disagree',
+          KSUID_ERROR_CODES.CORRUPTION_DETECTED,
+          {
+            input: object,
+            expected: ksuid.buffer.toString("hex").toUpperCase(),
+            actual: String(raw),
+          }
+        );
+      }
+    }
+
+    return ksuid;
+  }
+
   static fromBytes(buffer: Buffer): KSUID {
     if (buffer == null) {
       throw KSUIDError.invalidInput(buffer, "buffer");
@@ -77,6 +707,82 @@ export class KSUID {
     return new KSUID(Buffer.from(buffer));
   }
 
+  /**
+   * Reconstructs a KSUID from an anchor and a delta produced by deltaFrom().
+   * Fails if the delta is longer than 20 bytes or the sum does not fit in
+   * 160 bits.
+   */
+  static applyDelta(anchor: KSUID, delta: Buffer): KSUID {
+    if (delta == null) {
+      throw KSUIDError.invalidInput(delta, "delta");
+    }
+
+    if (delta.length > KSUID_LENGTH) {
+      throw new KSUIDError(
+        `Invalid delta: expected at most ${KSUID_LENGTH} bytes, got ${delta.length}`,
+        KSUID_ERROR_CODES.INVALID_BUFFER_SIZE,
+        {
+          input: delta,
+          expected: `at most ${KSUID_LENGTH} bytes`,
+          actual: `${delta.length} bytes`,
+        }
+      );
+    }
+
+    const diff = delta.length === 0 ? 0n : BigInt("0x" + delta.toString("hex"));
+    const value = ksuidToBigInt(anchor) + diff;
+    if (value >= 1n << BigInt(KSUID_LENGTH * 8)) {
+      throw new KSUIDError(
+        "Invalid delta: result exceeds the maximum KSUID",
+        KSUID_ERROR_CODES.INVALID_INPUT,
+        { input: delta, expected: "delta within KSUID range" }
+      );
+    }
+
+    return bigIntToKSUID(value);
+  }
+
+  /**
+   * Returns the canonical 27-character form of a KSUID string. Every value
+   * below 2^160 has exactly one such form, so the result is suitable as a
+   * deduplication key. Strings that decode to a value that does not fit in
+   * 160 bits are rejected rather than silently truncated.
+   */
+  static canonicalize(s: string): string {
+    const canonical = KSUID.parse(s).toString();
+    if (canonical !== s) {
+      throw new KSUIDError(
+        `Invalid KSUID string: "${s}" exceeds the maximum value "${MAX_STRING}"`,
+        KSUID_ERROR_CODES.INVALID_INPUT,
+        { input: s, expected: `value <= "${MAX_STRING}"`, actual: s }
+      );
+    }
+    return canonical;
+  }
+
+  /**
+   * Reports whether s is a canonical KSUID string: 27 base62 characters
+   * encoding a value that fits in 160 bits. Nothing is decoded or allocated.
+   * The alphabet is in ASCII order, so the range check is a plain string
+   * comparison against the maximum KSUID.
+   */
+  static isValid(s: string): boolean {
+    if (typeof s !== "string" || s.length !== STRING_LENGTH) {
+      return false;
+    }
+    for (let i = 0; i < STRING_LENGTH; i++) {
+      const c = s.charCodeAt(i);
+      const isBase62 =
+        (c >= 0x30 && c <= 0x39) ||
+        (c >= 0x41 && c <= 0x5a) ||
+        (c >= 0x61 && c <= 0x7a);
+      if (!isBase62) {
+        return false;
+      }
+    }
+    return s <= MAX_STRING;
+  }
+
   static parseOrNil(s: string): KSUID {
     try {
       return KSUID.parse(s);
@@ -85,6 +791,150 @@ export class KSUID {
     }
   }
 
+  /**
+   * Parses s like KSUID.parse(), for KSUIDs written as literals in source
+   * where a failure is a programming error. The thrown KSUIDError names the
+   * offending string and keeps the underlying parse error as its cause.
+   */
+  static mustParse(s: string): KSUID {
+    try {
+      return KSUID.parse(s);
+    } catch (error) {
+      if (!isKSUIDError(error)) {
+        throw error;
+      }
+      throw new KSUIDError(
+        `KSUID.mustParse("${s}"): ${error.message}`,
+        error.code,
+        {
+          input: s,
+          expected: error.expected,
+          actual: error.actual,
+          cause: error,
+        }
+      );
+    }
+  }
+
+  /**
+   * Parses s and checks its time against now, for KSUIDs accepted as tokens.
+   * Throws TIMESTAMP_EXPIRED when the KSUID is more than maxAge milliseconds
+   * older than now, and TIMESTAMP_IN_FUTURE when it is more than maxSkew
+   * milliseconds ahead of now, allowing for clock skew between services.
+   * The KSUID's time is the start of its second.
+   */
+  static parseWithinWindow(
+    s: string,
+    now: Date,
+    maxAge: number,
+    maxSkew = 0
+  ): KSUID {
+    for (const [name, value] of [
+      ["maxAge", maxAge],
+      ["maxSkew", maxSkew],
+    ] as const) {
+      if (!(value >= 0)) {
+        throw new KSUIDError(
+          `Invalid ${name}: must be a non-negative number of milliseconds, got ${value}`,
+          KSUID_ERROR_CODES.INVALID_INPUT,
+          {
+            input: value,
+            expected: "non-negative milliseconds",
+            actual: String(value),
+          }
+        );
+      }
+    }
+
+    const ksuid = KSUID.parse(s);
+    const time = timestampToTime(ksuid.timestamp).getTime();
+    const current = now.getTime();
+
+    if (current - time > maxAge) {
+      throw new KSUIDError(
+        `KSUID expired: created ${new Date(time).toISOString()}, more than ${maxAge}ms before ${now.toISOString()}`,
+        KSUID_ERROR_CODES.TIMESTAMP_EXPIRED,
+        {
+          input: s,
+          expected: `at most ${maxAge}ms old`,
+          actual: `${current - time}ms old`,
+        }
+      );
+    }
+    if (time - current > maxSkew) {
+      throw new KSUIDError(
+        `KSUID in the future: created ${new Date(time).toISOString()}, more than ${maxSkew}ms after ${now.toISOString()}`,
+        KSUID_ERROR_CODES.TIMESTAMP_IN_FUTURE,
+        {
+          input: s,
+          expected: `at most ${maxSkew}ms ahead`,
+          actual: `${time - current}ms ahead`,
+        }
+      );
+    }
+    return ksuid;
+  }
+
+  /**
+   * Parses a string produced by compactString(), padding it back to 27
+   * characters with leading '0's before decoding. Canonical 27 character
+   * strings are accepted unchanged.
+   */
+  static parseCompact(s: string): KSUID {
+    if (s == null) {
+      throw KSUIDError.invalidInput(s, "string");
+    }
+
+    if (s.length < 1 || s.length > STRING_LENGTH) {
+      throw new KSUIDError(
+        `Invalid compact KSUID string: expected 1 to ${STRING_LENGTH} characters, got ${s.length}`,
+        KSUID_ERROR_CODES.INVALID_LENGTH,
+        {
+          input: s,
+          expected: `1 to ${STRING_LENGTH} characters`,
+          actual: `${s.length} characters`,
+        }
+      );
+    }
+
+    return KSUID.parse(s.padStart(STRING_LENGTH, "0"));
+  }
+
+  /**
+   * Parses every string in strs, for bulk imports. On the first failure it
+   * throws a KSUIDError naming the index and the offending string, with the
+   * code of the underlying parse error and that error as its cause.
+   */
+  static parseAll(strs: string[]): KSUID[] {
+    return strs.map((s, i) => {
+      try {
+        return KSUID.parse(s);
+      } catch (error) {
+        if (!isKSUIDError(error)) {
+          throw error;
+        }
+        throw new KSUIDError(
+          `Invalid KSUID at index ${i} ("${s}"): ${error.message}`,
+          error.code,
+          {
+            input: s,
+            expected: error.expected,
+            actual: error.actual,
+            cause: error,
+          }
+        );
+      }
+    });
+  }
+
+  /**
+   * Parses every string in strs like KSUID.parseAll(), but never throws:
+   * entries that fail to parse become KSUID.nil, keeping indexes aligned.
+   */
+  static parseAllOrNil(strs: string[]): KSUID[] {
+    return strs.map(s => KSUID.parseOrNil(s));
+  }
+
   static fromPartsOrNil(timestamp: number, payload: Buffer): KSUID {
     try {
       return KSUID.fromParts(timestamp, payload);
@@ -109,7 +959,18 @@ export class KSUID {
     return this.buffer.readUInt32BE(0);
   }
 
+  /**
+   * A fresh copy of the 16-byte payload. It never aliases this KSUID's
+   * storage, so callers may modify it freely; payloadInto() copies into an
+   * existing buffer instead of allocating.
+   */
   get payload(): Buffer {
+    return Buffer.from(this.buffer.subarray(TIMESTAMP_LENGTH));
+  }
+
+  // The payload as a view of this KSUID's storage, for internal reads that
+  // must not pay for the payload getter's copy. Never hand it to callers.
+  private payloadView(): Buffer {
     return this.buffer.subarray(TIMESTAMP_LENGTH);
   }
 
@@ -117,18 +978,387 @@ export class KSUID {
     return Base62.encode(this.buffer);
   }
 
+  /**
+   * Returns the base62 string with its leading '0' characters removed, keeping
+   * at least one character, for shorter URLs. This gives up the fixed 27
+   * character width, so compact strings do not sort correctly as plain text;
+   * KSUID.parseCompact() restores the KSUID.
+   */
+  compactString(): string {
+    const s = this.toString();
+    let start = 0;
+    while (start < STRING_LENGTH - 1 && s[start] === "0") {
+      start++;
+    }
+    return s.slice(start);
+  }
+
+  /**
+   * Returns the 20 raw bytes. For speed this is the KSUID's own storage, not a
+   * copy, and must not be modified; use marshalBinary() for a copy.
+   */
   toBuffer(): Buffer {
     return this.buffer;
   }
 
+  /**
+   * Returns a file name for this KSUID with the given extension. A leading dot
+   * is added to ext when missing, and an empty ext yields the bare string.
+   * Base62 strings are safe as file names on all common operating systems.
+   */
+  filename(ext = ""): string {
+    if (ext !== "" && !ext.startsWith(".")) {
+      ext = "." + ext;
+    }
+    return this.toString() + ext;
+  }
+
+  /**
+   * Returns a copy of the 16-byte payload as the bytes of a UUID. The 4-byte
+   * timestamp is not part of the UUID and is lost; KSUID.fromUUID() needs it
+   * supplied separately to rebuild the KSUID.
+   */
+  toUUID(): Buffer {
+    return Buffer.from(this.buffer.subarray(TIMESTAMP_LENGTH));
+  }
+
+  /**
+   * Formats the 16-byte payload as a canonical lowercase 8-4-4-4-12 UUID
+   * string. The conversion is lossless for the payload; the timestamp is not
+   * included and must be supplied separately to KSUID.fromPayloadUUID().
+   */
+  payloadUUID(): string {
+    const hex = this.payloadView().toString("hex");
+    return [
+      hex.slice(0, 8),
+      hex.slice(8, 12),
+      hex.slice(12, 16),
+      hex.slice(16, 20),
+      hex.slice(20),
+    ].join("-");
+  }
+
+  /**
+   * Returns a quoted HTTP entity tag derived from a 64-bit FNV-1a hash of the
+   * 20 bytes. KSUIDs are immutable, so the tag is stable for the lifetime of
+   * the resource it identifies.
+   */
+  etag(): string {
+    return `"${fnv1a64(this.buffer).toString(16).padStart(16, "0")}"`;
+  }
+
+  /**
+   * Returns the hex SHA-256 digest of the payload, a deterministic seed for
+   * avatar generation services. The payload never changes, so the seed is
+   * stable for the lifetime of the entity.
+   */
+  avatarSeed(): string {
+    return crypto.createHash("sha256").update(this.payloadView()).digest("hex");
+  }
+
+  /**
+   * Returns count 64-bit hashes of the 20 bytes for use with a bloom filter.
+   *
+   * The hashes use double hashing: the first two 64-bit words of a SHA-256
+   * digest give base hashes h1 and h2, and hash i is h1 + i * h2 mod 2^64.
+   * h2 is forced odd, so the hashes are pairwise distinct for any count up to
+   * 2^64. They depend only on the KSUID and are stable across runs.
+   */
+  bloomHashes(count: number): bigint[] {
+    if (!Number.isInteger(count) || count < 0) {
+      throw new KSUIDError(
+        `Invalid hash count: must be a non-negative integer, got ${count}`,
+        KSUID_ERROR_CODES.INVALID_INPUT,
+        {
+          input: count,
+          expected: "non-negative integer",
+          actual: String(count),
+        }
+      );
+    }
+
+    const digest = crypto.createHash("sha256").update(this.buffer).digest();
+    const h1 = digest.readBigUInt64BE(0);
+    const h2 = digest.readBigUInt64BE(8) | 1n;
+
+    const hashes: bigint[] = [];
+    for (let i = 0; i < count; i++) {
+      hashes.push((h1 + BigInt(i) * h2) & 0xffffffffffffffffn);
+    }
+    return hashes;
+  }
+
+  /**
+   * Returns a short code such as "7KQ2-M9XF" for reading an ID aloud in
+   * support conversations. Seven characters come from a SHA-256 hash of the
+   * KSUID and the eighth is a check character that catches a single misheard
+   * character. The alphabet omits 0, O, 1, I and L.
+   *
+   * The code is a lookup aid, not an encoding: it cannot be converted back to
+   * the KSUID and different KSUIDs may share a code.
+   */
+  spokenCode(): string {
+    const digest = crypto.createHash("sha256").update(this.buffer).digest();
+    const base = SPOKEN_ALPHABET.length;
+
+    let code = "";
+    let check = 0;
+    for (let i = 0; i < 7; i++) {
+      const value = digest[i] % base;
+      code += SPOKEN_ALPHABET[value];
+      check += (i + 1) * value;
+    }
+    code += SPOKEN_ALPHABET[check % base];
+
+    return `${code.slice(0, 4)}-${code.slice(4)}`;
+  }
+
+  /**
+   * Returns the base62 string of a KSUID with this timestamp and a zero
+   * payload. All KSUIDs generated in the same second share the same value,
+   * which makes it a stable per-second grouping key.
+   */
+  secondId(): string {
+    return KSUID.fromParts(
+      this.timestamp,
+      Buffer.alloc(PAYLOAD_LENGTH)
+    ).toString();
+  }
+
+  /**
+   * Returns the 32-character RFC 4648 base32hex encoding of the 20 bytes,
+   * using the extended hex alphabet (0-9, A-V) without padding.
+   *
+   * This is distinct from the canonical base62 string. Its alphabet is in
+   * ASCII order and case-insensitive, so it keeps sort order and is safe for
+   * DNS labels and case-insensitive file systems.
+   */
+  toBase32Hex(): string {
+    return ksuidToBigInt(this).toString(32).padStart(32, "0").toUpperCase();
+  }
+
+  /**
+   * Returns a lossy 12-byte key made of the 4-byte timestamp followed by the
+   * high 8 bytes of the payload, for storage limited to 96-bit keys. Keys sort
+   * in the same time order as the KSUIDs they come from.
+   *
+   * Dropping the low 8 payload bytes leaves 64 random bits per second, so two
+   * KSUIDs from the same second collide with probability about n^2 / 2^65 for
+   * n IDs in that second, and KSUIDs produced by next() or a Sequence from the
+   * same seed usually share a key.
+   */
+  key96(): Buffer {
+    return Buffer.from(this.buffer.subarray(0, 12));
+  }
+
+  /**
+   * Writes the 27 character string form into dst at offset as ASCII bytes and
+   * returns the offset just past it, like Buffer.write(). Serializing many
+   * KSUIDs into one pre-sized buffer this way allocates nothing per KSUID.
+   * Throws if dst has fewer than 27 bytes available at offset.
+   */
+  appendString(dst: Buffer, offset = 0): number {
+    checkAppendRoom(dst, offset, STRING_LENGTH);
+    Base62.encodeInto(this.buffer, dst, offset);
+    return offset + STRING_LENGTH;
+  }
+
+  /**
+   * Copies the 20 raw bytes into dst at offset and returns the offset just
+   * past them. Throws if dst has fewer than 20 bytes available at offset.
+   */
+  appendBytes(dst: Buffer, offset = 0): number {
+    checkAppendRoom(dst, offset, KSUID_LENGTH);
+    this.buffer.copy(dst, offset);
+    return offset + KSUID_LENGTH;
+  }
+
+  /**
+   * Writes the 20 raw bytes to w, typically a Node.js Writable, and returns
+   * the number of bytes written. A copy is written, so the stream never holds
+   * a reference to this KSUID's buffer. Read them back with readKSUID().
+   */
+  writeTo(w: { write(chunk: Buffer): unknown }): number {
+    w.write(Buffer.from(this.buffer));
+    return KSUID_LENGTH;
+  }
+
+  /**
+   * Returns a copy of the 20 raw bytes, the compact binary form and the
+   * inverse of KSUID.unmarshalBinary(). Unlike toBuffer() the result does not
+   * alias this KSUID's storage.
+   */
+  marshalBinary(): Buffer {
+    return Buffer.from(this.buffer);
+  }
+
+  /**
+   * Copies the 16-byte payload into dst at offset without allocating and
+   * returns the offset just past it. Throws if dst has fewer than 16 bytes
+   * available at offset.
+   */
+  payloadInto(dst: Buffer, offset = 0): number {
+    checkAppendRoom(dst, offset, PAYLOAD_LENGTH);
+    this.buffer.copy(dst, offset, TIMESTAMP_LENGTH);
+    return offset + PAYLOAD_LENGTH;
+  }
+
+  /**
+   * Returns the 27 character string form as UTF-8 bytes, the inverse of
+   * KSUID.unmarshalText(). The nil KSUID marshals to its canonical string of
+   * zeros rather than to empty text.
+   */
+  marshalText(): Buffer {
+    return Buffer.from(this.toString(), "utf8");
+  }
+
+  /**
+   * Returns a 27 character base62 string hiding the timestamp and ordering of
+   * this KSUID, for exposing IDs publicly. The 20 bytes pass through an
+   * 8-round Feistel network keyed with HMAC-SHA256, a keyed permutation, so
+   * KSUID.deobfuscate() with the same key recovers the KSUID exactly.
+   *
+   * This is obfuscation, not encryption in a vetted mode: it is only as strong
+   * as the key, which should be at least 32 random bytes kept server-side, and
+   * it should not be treated as a security boundary.
+   */
+  obfuscate(key: Buffer): string {
+    checkObfuscationKey(key);
+    return Base62.encode(feistel(this.buffer, key, false));
+  }
+
+  /**
+   * Returns the string form so JSON.stringify() writes a KSUID as a quoted
+   * string instead of an object. KSUID.fromJSON() reverses it.
+   */
+  toJSON(): string {
+    return this.toString();
+  }
+
+  /**
+   * Returns the value to store in a database column, the 27 character string
+   * form. KSUID.scan() reads it back.
+   */
+  value(): string {
+    return this.toString();
+  }
+
+  /**
+   * Returns an opaque, URL-safe pagination cursor for this KSUID. The cursor
+   * is a one character format version followed by the base62 string, so it
+   * needs no escaping in query strings. Decode it with KSUID.parseCursor().
+   */
+  cursor(): string {
+    return CURSOR_VERSION + this.toString();
+  }
+
+  /**
+   * Reports whether all 20 bytes are zero, i.e. this is KSUID.nil. Useful for
+   * telling an absent value, such as a scanned NULL, from a real one. The
+   * check reads the bytes in place and allocates nothing.
+   */
   isNil(): boolean {
-    return this.buffer.equals(KSUID.nil.buffer);
+    for (let i = 0; i < KSUID_LENGTH; i++) {
+      if (this.buffer[i] !== 0) {
+        return false;
+      }
+    }
+    return true;
   }
 
   compare(other: KSUID): number {
     return this.buffer.compare(other.buffer);
   }
 
+  /**
+   * Reports whether this KSUID sorts before other, i.e. compare(other) < 0.
+   */
+  before(other: KSUID): boolean {
+    return this.compare(other) < 0;
+  }
+
+  /**
+   * Reports whether this KSUID sorts after other, i.e. compare(other) > 0.
+   */
+  after(other: KSUID): boolean {
+    return this.compare(other) > 0;
+  }
+
+  /**
+   * Reports whether both KSUIDs hold the same 20 bytes.
+   */
+  equals(other: KSUID): boolean {
+    return this.compare(other) === 0;
+  }
+
+  /**
+   * Reports whether both KSUIDs have the same timestamp, i.e. were generated
+   * in the same second. The payload is ignored entirely.
+   */
+  sameTime(other: KSUID): boolean {
+    return this.timestamp === other.timestamp;
+  }
+
+  /**
+   * Returns the difference between this KSUID and anchor as a minimal
+   * big-endian unsigned integer: leading zero bytes are dropped and a zero
+   * difference encodes as an empty buffer. Nearby KSUIDs therefore produce
+   * deltas of only a few bytes. The encoding is unsigned, so this KSUID must
+   * not be less than anchor. Use KSUID.applyDelta() to reverse it.
+   */
+  deltaFrom(anchor: KSUID): Buffer {
+    if (this.compare(anchor) < 0) {
+      throw new KSUIDError(
+        "Invalid delta: KSUID is less than the anchor",
+        KSUID_ERROR_CODES.INVALID_INPUT,
+        {
+          input: this.toString(),
+          expected: `KSUID >= ${anchor.toString()}`,
+          actual: this.toString(),
+        }
+      );
+    }
+
+    const diff = ksuidToBigInt(this) - ksuidToBigInt(anchor);
+    if (diff === 0n) {
+      return Buffer.alloc(0);
+    }
+
+    let hex = diff.toString(16);
+    if (hex.length % 2 === 1) {
+      hex = "0" + hex;
+    }
+    return Buffer.from(hex, "hex");
+  }
+
+  /**
+   * Reports whether the timestamp lies within [now - maxPast, now + maxFuture],
+   * with both tolerances in milliseconds. Every 20-byte value is a structurally
+   * valid KSUID, so this is a tunable sanity check for rejecting garbage or
+   * byte-swapped IDs whose time is implausibly far from now.
+   */
+  isPlausible(now: Date, maxFuture: number, maxPast: number): boolean {
+    const time = timestampToTime(this.timestamp).getTime();
+    const current = now.getTime();
+    return time <= current + maxFuture && time >= current - maxPast;
+  }
+
+  /**
+   * Compares with another KSUID like compare(), and additionally reports
+   * whether both share the same timestamp. When sameSecond is true, any
+   * ordering decision came down to the random payload rather than real time.
+   */
+  compareDetailed(other: KSUID): { result: number; sameSecond: boolean } {
+    for (let i = 0; i < KSUID_LENGTH; i++) {
+      const a = this.buffer[i];
+      const b = other.buffer[i];
+      if (a !== b) {
+        return { result: a < b ? -1 : 1, sameSecond: i >= TIMESTAMP_LENGTH };
+      }
+    }
+    return { result: 0, sameSecond: true };
+  }
+
   // Next returns the next KSUID after this one
   next(): KSUID {
     const timestamp = this.timestamp;
@@ -136,14 +1366,25 @@ export class KSUID {
     const nextPayload = payload.add(Uint128.one());
 
     // Check for payload overflow - if it wrapped to zero, increment timestamp
+    // (wrapping the maximum KSUID around to nil, as prev() does in reverse)
     if (nextPayload.isZero()) {
-      const nextTimestamp = timestamp + 1;
+      const nextTimestamp = (timestamp + 1) >>> 0;
       return KSUID.fromBytes(nextPayload.ksuid(nextTimestamp));
     } else {
       return KSUID.fromBytes(nextPayload.ksuid(timestamp));
     }
   }
 
+  /**
+   * Returns the KSUID n positions after this one, treating the 20 bytes as a
+   * 160-bit number so the payload carries into the timestamp exactly as n
+   * calls to next() would. Past the maximum KSUID the value wraps around to
+   * nil, matching next(). n must be a uint64.
+   */
+  nextN(n: bigint): KSUID {
+    return this.offsetBy(checkStepCount(n));
+  }
+
   // Prev returns the previous KSUID before this one
   prev(): KSUID {
     const timestamp = this.timestamp;
@@ -162,4 +1403,239 @@ export class KSUID {
       return KSUID.fromBytes(prevPayload.ksuid(timestamp));
     }
   }
+
+  /**
+   * Returns the KSUID n positions before this one, borrowing from the
+   * timestamp exactly as n calls to prev() would. Below nil the value wraps
+   * around to the maximum KSUID, matching prev(). n must be a uint64.
+   */
+  prevN(n: bigint): KSUID {
+    return this.offsetBy(-checkStepCount(n));
+  }
+
+  private offsetBy(delta: bigint): KSUID {
+    const value = ksuidToBigInt(this) + delta;
+    return bigIntToKSUID(BigInt.asUintN(KSUID_LENGTH * 8, value));
+  }
+
+  /**
+   * Returns a copy of this KSUID with the payload replaced. The payload must
+   * be exactly 16 bytes and is copied, so later changes to it have no effect.
+   */
+  withPayload(payload: Buffer): KSUID {
+    return KSUID.fromParts(this.timestamp, payload);
+  }
+
+  /**
+   * Returns a copy of this KSUID with the timestamp replaced by the second
+   * containing t. Times outside the KSUID range are clamped as in
+   * KSUID.minForTime().
+   */
+  withTimestamp(t: Date): KSUID {
+    return KSUID.fromParts(timeToTimestamp(t), this.payloadView());
+  }
+
+  /**
+   * Returns a KSUID with this KSUID's timestamp and a fresh random payload,
+   * retrying in the astronomically unlikely case that the payload matches, so
+   * the result is always distinct from this KSUID but shares its second. The
+   * two are not ordered relative to each other.
+   */
+  sameSecondDistinct(): KSUID {
+    let payload: Buffer;
+    do {
+      payload = crypto.randomBytes(PAYLOAD_LENGTH);
+    } while (payload.equals(this.payloadView()));
+    return KSUID.fromParts(this.timestamp, payload);
+  }
+
+  /**
+   * Returns a deterministic KSUID related to this one. The payload is derived
+   * from a SHA-256 hash of this KSUID's payload and index, and the timestamp is
+   * kept, so sibling(0), sibling(1), ... are distinct from each other and the
+   * same (ksuid, index) pair always yields the same result.
+   */
+  sibling(index: number): KSUID {
+    if (!Number.isInteger(index) || index < 0 || index > 0xffffffff) {
+      throw new KSUIDError(
+        `Invalid sibling index: must be uint32 (0 to 4294967295), got ${index}`,
+        KSUID_ERROR_CODES.INVALID_INPUT,
+        {
+          input: index,
+          expected: "uint32 (0 to 4294967295)",
+          actual: String(index),
+        }
+      );
+    }
+
+    const suffix = Buffer.alloc(4);
+    suffix.writeUInt32BE(index, 0);
+    const digest = crypto
+      .createHash("sha256")
+      .update(this.payloadView())
+      .update(suffix)
+      .digest();
+    return KSUID.fromParts(this.timestamp, digest.subarray(0, PAYLOAD_LENGTH));
+  }
+
+  /**
+   * Returns the time elapsed between the KSUID epoch and this KSUID's
+   * timestamp, in milliseconds.
+   */
+  sinceEpoch(): number {
+    return this.timestamp * 1000;
+  }
+
+  /**
+   * Returns the half-open interval [earliest, latest) containing the instant
+   * this KSUID was generated. Timestamps have one second resolution, so the
+   * interval is exactly one second wide; latest itself is excluded.
+   */
+  timeRange(): { earliest: Date; latest: Date } {
+    const time = timestampToTime(this.timestamp).getTime();
+    return { earliest: new Date(time), latest: new Date(time + 1000) };
+  }
+
+  /**
+   * Returns the position of this KSUID among all KSUIDs sharing its second, as
+   * payload / 2^128 in the range [0, 1). Only the top 53 bits of the payload
+   * contribute, which is the precision of a double.
+   */
+  secondFraction(): number {
+    const high = this.buffer.readBigUInt64BE(TIMESTAMP_LENGTH);
+    return Number(high >> 11n) / 2 ** 53;
+  }
+
+  /**
+   * Returns a two-dimensional partition key: the start of the time window of
+   * the given length (in milliseconds, aligned to the Unix epoch) containing
+   * this KSUID, and a shard in [0, buckets) derived from the payload. KSUIDs
+   * with the same payload in the same window always map to the same pair.
+   */
+  timeShard(
+    window: number,
+    buckets: number
+  ): { bucketTime: Date; shard: number } {
+    if (!(window > 0)) {
+      throw new KSUIDError(
+        `Invalid window: must be a positive duration, got ${window}`,
+        KSUID_ERROR_CODES.INVALID_INPUT,
+        {
+          input: window,
+          expected: "positive duration",
+          actual: String(window),
+        }
+      );
+    }
+    if (!Number.isInteger(buckets) || buckets < 1) {
+      throw new KSUIDError(
+        `Invalid buckets: must be a positive integer, got ${buckets}`,
+        KSUID_ERROR_CODES.INVALID_INPUT,
+        {
+          input: buckets,
+          expected: "positive integer",
+          actual: String(buckets),
+        }
+      );
+    }
+
+    const time = timestampToTime(this.timestamp).getTime();
+    const bucketTime = new Date(time - (time % window));
+    const high = this.buffer.readBigUInt64BE(TIMESTAMP_LENGTH);
+    const shard = Number(high % BigInt(buckets));
+    return { bucketTime, shard };
+  }
+
+  /**
+   * Picks a node by rendezvous (highest random weight) hashing: each node is
+   * scored with the first 64 bits of a SHA-256 hash of the payload followed by
+   * the node name, and the highest score wins, with ties going to the smaller
+   * name. Unlike modulo sharding, removing a node only remaps the KSUIDs that
+   * were assigned to it, and adding one only takes KSUIDs for the new node.
+   */
+  rendezvousNode(nodes: string[]): string {
+    if (nodes == null || nodes.length === 0) {
+      throw new KSUIDError(
+        "Invalid nodes: at least one node is required",
+        KSUID_ERROR_CODES.INVALID_INPUT,
+        {
+          input: nodes,
+          expected: "non-empty array",
+          actual: String(nodes?.length ?? nodes),
+        }
+      );
+    }
+
+    const payload = this.payloadView();
+    let best = nodes[0];
+    let bestScore = -1n;
+    for (const node of nodes) {
+      const score = crypto
+        .createHash("sha256")
+        .update(payload)
+        .update(node)
+        .digest()
+        .readBigUInt64BE(0);
+      if (score > bestScore || (score === bestScore && node < best)) {
+        best = node;
+        bestScore = score;
+      }
+    }
+    return best;
+  }
+
+  /**
+   * Returns the version stored in the top 4 bits of the payload by
+   * KSUID.randomVersioned(). For other KSUIDs the value is just random bits.
+   */
+  version(): number {
+    return this.buffer[TIMESTAMP_LENGTH] >> 4;
+  }
+
+  /**
+   * Returns a KSUID with the same payload whose timestamp is floored to a
+   * multiple of period (in milliseconds, truncated to whole seconds).
+   *
+   * Alignment is relative to the KSUID epoch (2014-05-13T16:53:20Z), not the
+   * Unix epoch, so for periods that do not divide the epoch offset evenly the
+   * bucket boundaries differ from Unix-aligned ones. Periods under a second
+   * return the KSUID unchanged.
+   */
+  alignTo(period: number): KSUID {
+    const seconds = Math.floor(period / 1000);
+    if (!(seconds >= 1)) {
+      return this;
+    }
+    const timestamp = this.timestamp - (this.timestamp % seconds);
+    return KSUID.fromParts(timestamp, this.payloadView());
+  }
+
+  /**
+   * Reports whether the last payload byte is the CRC-8 checksum of the rest of
+   * the payload, as set by KSUID.randomChecked().
+   */
+  verifyChecksum(): boolean {
+    const payload = this.payloadView();
+    return (
+      payload[PAYLOAD_LENGTH - 1] ===
+      crc8(payload.subarray(0, PAYLOAD_LENGTH - 1))
+    );
+  }
+
+  // Neighbors returns prev() and next() together, for range boundary tests
+  neighbors(): { prev: KSUID; next: KSUID } {
+    return { prev: this.prev(), next: this.next() };
+  }
+
+  // NextSecond returns a KSUID one second later with the same payload. The
+  // timestamp wraps around to zero after the maximum value.
+  nextSecond(): KSUID {
+    return KSUID.fromParts((this.timestamp + 1) >>> 0, this.payloadView());
+  }
+
+  // PrevSecond returns a KSUID one second earlier with the same payload. The
+  // timestamp wraps around to the maximum value below zero.
+  prevSecond(): KSUID {
+    return KSUID.fromParts((this.timestamp - 1) >>> 0, this.payloadView());
+  }
 }
@@ -0,0 +1,100 @@
+import * as crypto from "crypto";
+import { KSUID, EPOCH, RandomSource, timestampToTime } from "./ksuid";
+import { KSUIDError, KSUID_ERROR_CODES } from "./errors";
+
+/**
+ * MonotonicGenerator produces KSUIDs that are strictly increasing, even when
+ * many are generated within the same second.
+ *
+ * The first KSUID of each second has a random payload. Further KSUIDs in the
+ * same second are the previous value's next(), so they sort after it instead
+ * of at a random position within the second. If the clock moves backwards the
+ * generator keeps incrementing from its last output until time catches up.
+ *
+ * ```typescript
+ * const gen = new MonotonicGenerator();
+ * const a = gen.next();
+ * const b = gen.next(); // b.compare(a) === 1
+ * ```
+ *
+ * JavaScript runs each next() call to completion, so a single generator can
+ * be shared by all code in a process without additional locking.
+ */
+export class MonotonicGenerator {
+  private readonly clock: () => Date;
+  private readonly random: RandomSource;
+  private last: KSUID | null = null;
+
+  constructor(options: { clock?: () => Date; random?: RandomSource } = {}) {
+    this.clock = options.clock ?? (() => new Date());
+    this.random = options.random ?? crypto.randomBytes;
+  }
+
+  /**
+   * Next returns a KSUID strictly greater than any previously returned by this
+   * generator.
+   */
+  next(): KSUID {
+    const now = Math.floor(this.clock().getTime() / 1000) - EPOCH;
+
+    if (this.last !== null && now <= this.last.timestamp) {
+      this.last = this.last.next();
+    } else {
+      this.last = KSUID.fromParts(now, this.random(16));
+    }
+    return this.last;
+  }
+
+  /**
+   * Returns how many milliseconds the last KSUID's second is ahead of now, or
+   * 0 if it is not ahead or nothing has been generated yet. A stalled or
+   * backwards clock makes the generator carry into later seconds; drift
+   * reports how far it has run ahead of real time.
+   */
+  drift(now: Date): number {
+    if (this.last === null) {
+      return 0;
+    }
+    const start = timestampToTime(this.last.timestamp).getTime();
+    return Math.max(0, start - now.getTime());
+  }
+}
+
+/**
+ * Returns an endless async stream of strictly increasing KSUIDs produced by a
+ * MonotonicGenerator, for load tests and other continuous consumers.
+ *
+ * KSUIDs are generated in batches of bufferSize (default 64), and the stream
+ * yields to the event loop between batches. Once signal.aborted is true (an
+ * AbortController's signal works) no new batch is generated, but the KSUIDs
+ * already buffered are still delivered before the stream ends, so none are
+ * dropped. The stream is pull-based: a consumer that stops iterating leaves
+ * nothing running in the background.
+ */
+export async function* stream(
+  signal?: { readonly aborted: boolean },
+  options: { bufferSize?: number } = {}
+): AsyncGenerator<KSUID, void, undefined> {
+  const bufferSize = options.bufferSize ?? 64;
+  if (!Number.isInteger(bufferSize) || bufferSize < 1) {
+    throw new KSUIDError(
+      `Invalid buffer size: must be a positive integer, got ${bufferSize}`,
+      KSUID_ERROR_CODES.INVALID_INPUT,
+      {
+        input: bufferSize,
+        expected: "positive integer",
+        actual: String(bufferSize),
+      }
+    );
+  }
+
+  const generator = new MonotonicGenerator();
+  while (!signal?.aborted) {
+    const buffer: KSUID[] = [];
+    for (let i = 0; i < bufferSize; i++) {
+      buffer.push(generator.next());
+    }
+    yield* buffer;
+    await new Promise(resolve => setImmediate(resolve));
+  }
+}
@@ -0,0 +1,151 @@
+import { KSUID, ksuidToBigInt, bigIntToKSUID } from "./ksuid";
+import { KSUIDError, KSUID_ERROR_CODES } from "./errors";
+
+const KSUID_BYTE_LENGTH = 20;
+
+export { ksuidToBigInt, bigIntToKSUID };
+
+/**
+ * Returns n KSUIDs spaced at equal 160-bit intervals from lo to hi inclusive.
+ *
+ * For n >= 2 the first element is lo and the last is hi. Intermediate values
+ * are computed exactly as lo + (hi - lo) * i / (n - 1), truncating any
+ * fractional part, so the spacing varies by at most one between neighbours.
+ *
+ * When n is 1 the result is [lo], and when n is 0 or negative the result is
+ * empty. If lo is greater than hi the result descends from lo to hi.
+ */
+export function interpolate(lo: KSUID, hi: KSUID, n: number): KSUID[] {
+  if (n <= 0) {
+    return [];
+  }
+  if (n === 1) {
+    return [lo];
+  }
+
+  const start = ksuidToBigInt(lo);
+  const span = ksuidToBigInt(hi) - start;
+  const steps = BigInt(n - 1);
+
+  const result: KSUID[] = [];
+  for (let i = 0; i < n; i++) {
+    result.push(bigIntToKSUID(start + (span * BigInt(i)) / steps));
+  }
+  return result;
+}
+
+/**
+ * Estimates how many of totalIDs fall within [lo, hi], given that all of them
+ * lie within [fullLo, fullHi]. Both ranges are inclusive.
+ *
+ * The estimate assumes IDs are uniformly distributed over the full range,
+ * which holds for the random payload within a second but not across time
+ * when generation rate varies. The result is computed with exact 160-bit
+ * arithmetic and truncated. An empty range, or lo > hi, yields 0.
+ */
+export function estimateCount(
+  lo: KSUID,
+  hi: KSUID,
+  totalIDs: bigint,
+  fullLo: KSUID,
+  fullHi: KSUID
+): bigint {
+  const size = ksuidToBigInt(hi) - ksuidToBigInt(lo) + 1n;
+  const fullSize = ksuidToBigInt(fullHi) - ksuidToBigInt(fullLo) + 1n;
+  if (size <= 0n || fullSize <= 0n) {
+    return 0n;
+  }
+  return (totalIDs * size) / fullSize;
+}
+
+/**
+ * Returns the number of identical leading bits of two KSUIDs, from 0 (they
+ * differ in the most significant bit) to 160 (they are equal).
+ */
+export function commonPrefixBits(a: KSUID, b: KSUID): number {
+  const x = a.toBuffer();
+  const y = b.toBuffer();
+  for (let i = 0; i < KSUID_BYTE_LENGTH; i++) {
+    const diff = x[i] ^ y[i];
+    if (diff !== 0) {
+      return i * 8 + Math.clz32(diff) - 24;
+    }
+  }
+  return KSUID_BYTE_LENGTH * 8;
+}
+
+/**
+ * Returns the n-th KSUID of the inclusive range [lo, hi], counting from lo at
+ * n = 0, so n = hi - lo addresses hi itself. Throws if n is negative or beyond
+ * the end of the range.
+ */
+export function nthInRange(lo: KSUID, hi: KSUID, n: bigint): KSUID {
+  const start = ksuidToBigInt(lo);
+  const size = ksuidToBigInt(hi) - start + 1n;
+  if (n < 0n || n >= size) {
+    const count = size > 0n ? size : 0n;
+    throw new KSUIDError(
+      `Invalid rank: ${n} is outside a range of ${count} KSUIDs`,
+      KSUID_ERROR_CODES.INVALID_INPUT,
+      { input: n, expected: `rank below ${count}`, actual: n.toString() }
+    );
+  }
+  return bigIntToKSUID(start + n);
+}
+
+/**
+ * Returns the midpoint of lo and hi, a KSUID strictly greater than lo and
+ * strictly less than hi. This supports fractional indexing, where an item is
+ * inserted between two neighbours without renumbering. Throws when no such
+ * value exists, i.e. when hi is not at least two above lo.
+ */
+export function between(lo: KSUID, hi: KSUID): KSUID {
+  const a = ksuidToBigInt(lo);
+  const b = ksuidToBigInt(hi);
+  if (b - a < 2n) {
+    throw new KSUIDError(
+      `No KSUID exists strictly between ${lo.toString()} and ${hi.toString()}`,
+      KSUID_ERROR_CODES.INVALID_INPUT,
+      {
+        input: [lo.toString(), hi.toString()],
+        expected: "hi at least two above lo",
+        actual: `difference of ${b - a}`,
+      }
+    );
+  }
+  return bigIntToKSUID(a + (b - a) / 2n);
+}
+
+/**
+ * Returns the absolute difference between two KSUIDs as unsigned 160-bit
+ * integers, timestamp and payload combined. The result is symmetric, so the
+ * order of the arguments does not matter, and identical KSUIDs yield 0. It
+ * equals the number of next() steps from the smaller KSUID to the larger.
+ */
+export function distance(a: KSUID, b: KSUID): bigint {
+  const diff = ksuidToBigInt(a) - ksuidToBigInt(b);
+  return diff < 0n ? -diff : diff;
+}
+
+/**
+ * Calls fn with every KSUID from start to end inclusive, in ascending order,
+ * stopping early as soon as fn returns false. Nothing is called when end is
+ * below start. Adjacent KSUIDs differ by one in the last payload byte, so
+ * even a single second holds 2^128 of them: this is only meant for tiny
+ * ranges, such as a handful of next() steps in exhaustive tests.
+ */
+export function range(
+  start: KSUID,
+  end: KSUID,
+  fn: (id: KSUID) => boolean | void
+): void {
+  if (start.compare(end) > 0) {
+    return;
+  }
+
+  for (let id = start; ; id = id.next()) {
+    if (fn(id) === false || id.compare(end) === 0) {
+      return;
+    }
+  }
+}
@@ -5,7 +5,10 @@ import { Buffer } from "buffer";
  * Sequence is a KSUID generator which produces a sequence of ordered KSUIDs
  * from a seed.
  *
- * Up to 65536 KSUIDs can be generated for a single seed.
+ * Up to 65536 KSUIDs can be generated for a single seed. The nth KSUID is the
+ * seed with its last two bytes (bytes 18 and 19, the end of the payload)
+ * overwritten by n as a big-endian uint16; every other byte, including the
+ * timestamp, is the seed's. The seed's own last two bytes are ignored.
  *
  * A typical usage of a Sequence looks like this:
  *
@@ -14,7 +17,11 @@ import { Buffer } from "buffer";
  * const id = seq.next();
  * ```
  *
- * Sequence values are not safe to use concurrently from multiple threads.
+ * A Sequence needs no locking within one JavaScript thread: next() is
+ * synchronous, so calls from concurrent async tasks cannot interleave and
+ * never return duplicate or out-of-order values. A Sequence cannot be shared
+ * between worker threads at all; passing one to a worker copies it, and both
+ * copies would then produce the same values. Give each worker its own seed.
  */
 export class Sequence {
   private seed: KSUID;
@@ -47,6 +54,9 @@ export class Sequence {
    * Bounds returns the inclusive min and max bounds of the KSUIDs that may be
    * generated by the sequence. If all ids have been generated already then the
    * returned min value is equal to the max.
+   *
+   * Before the first call to next() (or after reset()) this is the whole block
+   * reserved by the seed: the seed with bytes 18-19 set to 0x0000 and 0xFFFF.
    */
   bounds(): { min: KSUID; max: KSUID } {
     let count = this.count;
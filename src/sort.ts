@@ -1,4 +1,7 @@
 import { KSUID } from "./ksuid";
+import { KSUIDError, KSUID_ERROR_CODES } from "./errors";
+
+const KSUID_BYTE_LENGTH = 20;
 
 /**
  * Sorts the given array of KSUIDs in ascending order (in place).
@@ -33,6 +36,95 @@ export function compare(a: KSUID, b: KSUID): number {
   return a.compare(b);
 }
 
+/**
+ * Compares only the timestamps of two KSUIDs, returning -1, 0 or 1. The
+ * payload is ignored entirely, so KSUIDs from the same second compare equal
+ * whatever their payloads.
+ */
+export function compareTime(a: KSUID, b: KSUID): number {
+  return Math.sign(a.timestamp - b.timestamp);
+}
+
+/**
+ * Compares two KSUIDs using only bytes skipBytes through 19, returning -1, 0
+ * or 1. The first skipBytes bytes are ignored entirely, so this only agrees
+ * with compare() for KSUIDs known to share that prefix, such as IDs from one
+ * second (skipBytes = 4) or from a single Sequence.
+ */
+export function compareSuffix(a: KSUID, b: KSUID, skipBytes: number): number {
+  if (
+    !Number.isInteger(skipBytes) ||
+    skipBytes < 0 ||
+    skipBytes > KSUID_BYTE_LENGTH
+  ) {
+    throw new KSUIDError(
+      `Invalid skipBytes: must be 0 to ${KSUID_BYTE_LENGTH}, got ${skipBytes}`,
+      KSUID_ERROR_CODES.INVALID_INPUT,
+      {
+        input: skipBytes,
+        expected: `0 to ${KSUID_BYTE_LENGTH}`,
+        actual: String(skipBytes),
+      }
+    );
+  }
+
+  return a
+    .toBuffer()
+    .compare(
+      b.toBuffer(),
+      skipBytes,
+      KSUID_BYTE_LENGTH,
+      skipBytes,
+      KSUID_BYTE_LENGTH
+    );
+}
+
+/**
+ * Merges two sorted arrays into a new sorted array in linear time, keeping a
+ * single copy of KSUIDs that appear more than once. Both inputs must already
+ * be sorted in ascending order; this is not checked.
+ */
+export function merge(a: KSUID[], b: KSUID[]): KSUID[] {
+  const result: KSUID[] = [];
+  const push = (id: KSUID): void => {
+    if (result.length === 0 || result[result.length - 1].compare(id) !== 0) {
+      result.push(id);
+    }
+  };
+
+  let i = 0;
+  let j = 0;
+  while (i < a.length && j < b.length) {
+    if (a[i].compare(b[j]) <= 0) {
+      push(a[i++]);
+    } else {
+      push(b[j++]);
+    }
+  }
+  while (i < a.length) {
+    push(a[i++]);
+  }
+  while (j < b.length) {
+    push(b[j++]);
+  }
+  return result;
+}
+
+/**
+ * Reports whether two arrays hold equal KSUIDs in the same order.
+ */
+export function equal(a: KSUID[], b: KSUID[]): boolean {
+  if (a.length !== b.length) {
+    return false;
+  }
+  for (let i = 0; i < a.length; i++) {
+    if (a[i].compare(b[i]) !== 0) {
+      return false;
+    }
+  }
+  return true;
+}
+
 /**
  * Quicksort implementation for KSUID arrays (matches Go implementation)
  */
@@ -0,0 +1,22 @@
+import { KSUID, timeToTimestamp, timestampToTime } from "./ksuid";
+
+export { timeToTimestamp, timestampToTime };
+
+/**
+ * Returns the overlap, in milliseconds, between the windows
+ * [a's time, a's time + aDuration] and [b's time, b's time + bDuration], where
+ * durations are also in milliseconds. Disjoint or merely adjacent windows
+ * overlap by 0.
+ */
+export function overlapDuration(
+  a: KSUID,
+  aDuration: number,
+  b: KSUID,
+  bDuration: number
+): number {
+  const aStart = timestampToTime(a.timestamp).getTime();
+  const bStart = timestampToTime(b.timestamp).getTime();
+  const start = Math.max(aStart, bStart);
+  const end = Math.min(aStart + aDuration, bStart + bDuration);
+  return Math.max(0, end - start);
+}
@@ -301,6 +301,8 @@ test("Error codes contract", () => {
   assert.type(KSUID_ERROR_CODES.INVALID_INPUT, "string");
   assert.type(KSUID_ERROR_CODES.MALFORMED_DATA, "string");
   assert.type(KSUID_ERROR_CODES.CORRUPTION_DETECTED, "string");
+  assert.type(KSUID_ERROR_CODES.TIMESTAMP_EXPIRED, "string");
+  assert.type(KSUID_ERROR_CODES.TIMESTAMP_IN_FUTURE, "string");
   assert.type(KSUID_ERROR_CODES.SEQUENCE_EXHAUSTED, "string");
   assert.type(KSUID_ERROR_CODES.OPERATION_FAILED, "string");
 
@@ -312,6 +314,8 @@ test("Error codes contract", () => {
   assert.is(KSUID_ERROR_CODES.INVALID_INPUT, "INVALID_INPUT");
   assert.is(KSUID_ERROR_CODES.MALFORMED_DATA, "MALFORMED_DATA");
   assert.is(KSUID_ERROR_CODES.CORRUPTION_DETECTED, "CORRUPTION_DETECTED");
+  assert.is(KSUID_ERROR_CODES.TIMESTAMP_EXPIRED, "TIMESTAMP_EXPIRED");
+  assert.is(KSUID_ERROR_CODES.TIMESTAMP_IN_FUTURE, "TIMESTAMP_IN_FUTURE");
   assert.is(KSUID_ERROR_CODES.SEQUENCE_EXHAUSTED, "SEQUENCE_EXHAUSTED");
   assert.is(KSUID_ERROR_CODES.OPERATION_FAILED, "OPERATION_FAILED");
 });
@@ -8,7 +8,7 @@
  */
 
 import { performance } from "perf_hooks";
-import { KSUID, Sequence, sort, compare } from "../../src/index";
+import { KSUID, Sequence, sort, compare, randomBatch } from "../../src/index";
 
 interface BenchmarkResult {
   operation: string;
@@ -146,18 +146,52 @@ async function runBenchmarks(): Promise<void> {
     KSUID.random();
   });
 
+  // Bulk generation, against the same number of random() calls
+  await benchmark.run("Batch Generation (randomBatch x100)", 1000, () => {
+    randomBatch(100);
+  });
+
+  await benchmark.run("Batch Generation (random x100)", 1000, () => {
+    for (let i = 0; i < 100; i++) {
+      KSUID.random();
+    }
+  });
+
   // 2. KSUID Parsing Benchmark
   let parseIndex = 0;
   await benchmark.run("String Parsing", 100000, () => {
     KSUID.parse(testStrings[parseIndex++ % testStrings.length]);
   });
 
+  // Validation without parsing, against parse() with the result discarded
+  let validIndex = 0;
+  await benchmark.run("String Validation (isValid)", 100000, () => {
+    KSUID.isValid(testStrings[validIndex++ % testStrings.length]);
+  });
+
+  let parseCheckIndex = 0;
+  await benchmark.run("String Validation (parse)", 100000, () => {
+    try {
+      KSUID.parse(testStrings[parseCheckIndex++ % testStrings.length]);
+    } catch {
+      // Invalid input; only the outcome matters
+    }
+  });
+
   // 3. KSUID String Encoding Benchmark
   let encodeIndex = 0;
   await benchmark.run("String Encoding", 100000, () => {
     testKsuids[encodeIndex++ % testKsuids.length].toString();
   });
 
+  // Appending into one pre-sized buffer should leave the heap flat
+  const appendBuffer = Buffer.alloc(testKsuids.length * 27);
+  let appendIndex = 0;
+  await benchmark.run("Append String (pre-sized buffer)", 100000, () => {
+    const i = appendIndex++ % testKsuids.length;
+    testKsuids[i].appendString(appendBuffer, i * 27);
+  });
+
   // 4. Buffer Operations Benchmark
   let bufferIndex = 0;
   await benchmark.run("Buffer Conversion", 100000, () => {
@@ -1,6 +1,6 @@
 import { test } from "uvu";
 import * as assert from "uvu/assert";
-import { Base62 } from "../../src/base62";
+import { Base62, BASE62_ALPHABET } from "../../src/base62";
 import { Buffer } from "buffer";
 
 test("Base62 round-trip for all-zero buffer", () => {
@@ -79,4 +79,13 @@ test("Base62.decode() throws for invalid character", () => {
   );
 });
 
+test("BASE62_ALPHABET is the canonical ordering", () => {
+  assert.is(
+    BASE62_ALPHABET,
+    "0123456789ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz"
+  );
+  assert.is(new Set(BASE62_ALPHABET).size, 62);
+  assert.is(Base62.encode(Buffer.alloc(20, 0)), "0".repeat(27));
+});
+
 test.run();
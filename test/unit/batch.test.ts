@@ -0,0 +1,260 @@
+import { test } from "uvu";
+import * as assert from "uvu/assert";
+import { KSUID } from "../../src/ksuid";
+import {
+  validateBatch,
+  percentile,
+  countPerSecond,
+  findDuplicateSeconds,
+  longestRun,
+  randomBatch,
+  shuffledIndexedBatch,
+  backfillBatch,
+  generateBetween,
+  batchesOverlap,
+} from "../../src/batch";
+import { Buffer } from "buffer";
+
+const EPOCH = 1400000000;
+
+function at(unixSeconds: number, fill = 0): KSUID {
+  return KSUID.fromParts(unixSeconds - EPOCH, Buffer.alloc(16, fill));
+}
+
+test("validateBatch() accepts a normal batch", () => {
+  const now = new Date(1700000000 * 1000);
+  const ids = [at(1699999990), at(1700000000), at(1700000001)];
+  assert.equal(validateBatch(ids, now, 5000), []);
+  assert.equal(validateBatch([], now, 0), []);
+});
+
+test("validateBatch() reports future-dated IDs", () => {
+  const now = new Date(1700000000 * 1000);
+  const ids = [at(1700000000), at(1700003600), at(1699990000), at(1700000010)];
+  assert.equal(validateBatch(ids, now, 5000), [1, 3]);
+  assert.equal(validateBatch(ids, now, 60 * 60 * 1000), []);
+});
+
+test("percentile() p50 of an odd-length batch is the median", () => {
+  const ids = [1, 2, 3, 4, 5].map(i => at(1700000000 + i));
+  assert.is(percentile(ids, 50)!.compare(ids[2]), 0);
+});
+
+test("percentile() p50 of an even-length batch is the lower median", () => {
+  const ids = [1, 2, 3, 4].map(i => at(1700000000 + i));
+  assert.is(percentile(ids, 50)!.compare(ids[1]), 0);
+});
+
+test("percentile() extremes and landmarks", () => {
+  const ids = Array.from({ length: 100 }, (_, i) => at(1700000000 + i));
+  assert.is(percentile(ids, 0)!.compare(ids[0]), 0);
+  assert.is(percentile(ids, 90)!.compare(ids[89]), 0);
+  assert.is(percentile(ids, 99)!.compare(ids[98]), 0);
+  assert.is(percentile(ids, 100)!.compare(ids[99]), 0);
+});
+
+test("percentile() of an empty batch is null", () => {
+  assert.is(percentile([], 50), null);
+});
+
+test("percentile() rejects out-of-range percentiles", () => {
+  assert.throws(() => percentile([at(1700000000)], -1), /Invalid percentile/);
+  assert.throws(() => percentile([at(1700000000)], 101), /Invalid percentile/);
+});
+
+test("countPerSecond() counts clustered IDs", () => {
+  const ids = [
+    at(1700000000, 1),
+    at(1700000005, 1),
+    at(1700000000, 2),
+    at(1700000005, 2),
+    at(1700000000, 3),
+  ];
+
+  const counts = countPerSecond(ids);
+  assert.is(counts.size, 2);
+  assert.is(counts.get(1700000000), 3);
+  assert.is(counts.get(1700000005), 2);
+  assert.is(countPerSecond([]).size, 0);
+});
+
+test("findDuplicateSeconds() reports seconds with several IDs", () => {
+  const ids = [
+    at(1700000001),
+    at(1700000002),
+    at(1700000003, 1),
+    at(1700000004),
+    at(1700000003, 2),
+  ];
+  assert.equal(findDuplicateSeconds(ids), [1700000003]);
+});
+
+test("findDuplicateSeconds() of unique seconds is empty", () => {
+  const ids = [at(1700000001), at(1700000002), at(1700000003)];
+  assert.equal(findDuplicateSeconds(ids), []);
+  assert.equal(findDuplicateSeconds([]), []);
+});
+
+test("longestRun() identifies the longer of two runs", () => {
+  const first = at(1700000000);
+  const second = at(1700000100);
+  const ids = [
+    first,
+    first.next(),
+    first.next().next(),
+    second,
+    second.next(),
+    second.next().next(),
+    second.next().next().next(),
+    at(1700000200),
+  ];
+  assert.equal(longestRun(ids), { start: 3, length: 4 });
+});
+
+test("longestRun() of a batch without runs", () => {
+  const ids = [at(1700000000), at(1700000001), at(1700000002)];
+  assert.equal(longestRun(ids), { start: 0, length: 1 });
+  assert.equal(longestRun([]), { start: 0, length: 0 });
+});
+
+test("longestRun() spans a payload carry into the next second", () => {
+  const last = at(1700000000, 0xff);
+  const ids = [last.prev(), last, last.next()];
+  assert.equal(longestRun(ids), { start: 0, length: 3 });
+});
+
+test("shuffledIndexedBatch() indices invert the shuffle", () => {
+  const { ids, indices } = shuffledIndexedBatch(200);
+  assert.is(ids.length, 200);
+  assert.is(indices.length, 200);
+  assert.equal([...indices].sort((a, b) => a - b), indices.map((_, i) => i));
+
+  const restored: KSUID[] = new Array(ids.length);
+  ids.forEach((id, i) => {
+    restored[indices[i]] = id;
+  });
+  for (let i = 1; i < restored.length; i++) {
+    assert.is(restored[i - 1].compare(restored[i]), -1);
+  }
+});
+
+test("shuffledIndexedBatch() with invalid or empty sizes", () => {
+  assert.equal(shuffledIndexedBatch(0), { ids: [], indices: [] });
+  assert.throws(() => shuffledIndexedBatch(-1), /Invalid batch size/);
+});
+
+test("randomBatch() generates distinct KSUIDs for now", () => {
+  const before = Math.floor(Date.now() / 1000) - EPOCH;
+  const ids = randomBatch(1000);
+  const after = Math.floor(Date.now() / 1000) - EPOCH;
+
+  assert.is(ids.length, 1000);
+  assert.is(new Set(ids.map(id => id.toString())).size, 1000);
+  for (const id of ids) {
+    assert.ok(id.timestamp >= before && id.timestamp <= after);
+    assert.is(id.timestamp, ids[0].timestamp);
+  }
+  assert.equal(randomBatch(0), []);
+  assert.throws(() => randomBatch(-1), /Invalid batch size/);
+});
+
+test("backfillBatch() spans the range in sorted order", () => {
+  const start = new Date(1600000000 * 1000);
+  const end = new Date(1600000100 * 1000);
+  const ids = backfillBatch(start, end, 2000);
+
+  assert.is(ids.length, 2000);
+  for (let i = 1; i < ids.length; i++) {
+    assert.ok(ids[i - 1].compare(ids[i]) <= 0);
+  }
+
+  const seconds = ids.map(id => id.timestamp + EPOCH);
+  assert.is(seconds[0], 1600000000);
+  assert.is(seconds[seconds.length - 1], 1600000100);
+  assert.is(new Set(seconds).size, 101);
+});
+
+test("backfillBatch() with a single-second range", () => {
+  const t = new Date(1600000000 * 1000);
+  const ids = backfillBatch(t, t, 3);
+  assert.equal(
+    ids.map(id => id.timestamp + EPOCH),
+    [1600000000, 1600000000, 1600000000]
+  );
+});
+
+test("backfillBatch() rejects invalid arguments", () => {
+  const start = new Date(1600000000 * 1000);
+  const end = new Date(1600000100 * 1000);
+
+  assert.throws(() => backfillBatch(end, start, 10), /Invalid time range/);
+  assert.throws(
+    () => backfillBatch(new Date(1600000000100), new Date(1600000000900), 1),
+    /Invalid time range/
+  );
+  assert.throws(() => backfillBatch(start, end, 0), /Invalid batch size/);
+  assert.throws(() => backfillBatch(start, end, -5), /Invalid batch size/);
+});
+
+test("generateBetween() spaces timestamps evenly", () => {
+  const start = new Date(1600000000 * 1000);
+  const end = new Date(1600000100 * 1000);
+  const ids = generateBetween(start, end, 5);
+
+  assert.equal(
+    ids.map(id => id.timestamp + EPOCH),
+    [1600000000, 1600000025, 1600000050, 1600000075, 1600000100]
+  );
+});
+
+test("generateBetween() returns sorted KSUIDs", () => {
+  const t = new Date(1600000000 * 1000);
+  const ids = generateBetween(t, new Date(1600000002 * 1000), 50);
+
+  assert.is(ids.length, 50);
+  for (let i = 1; i < ids.length; i++) {
+    assert.ok(ids[i - 1].compare(ids[i]) <= 0);
+  }
+  assert.is(generateBetween(t, t, 1)[0].timestamp + EPOCH, 1600000000);
+  assert.equal(generateBetween(t, t, 0), []);
+});
+
+test("generateBetween() rejects invalid arguments", () => {
+  const start = new Date(1600000000 * 1000);
+  const end = new Date(1600000100 * 1000);
+
+  assert.throws(() => generateBetween(end, start, 10), /is before start/);
+  assert.throws(() => generateBetween(start, end, -1), /Invalid batch size/);
+  assert.throws(() => generateBetween(start, end, 1.5), /Invalid batch size/);
+  assert.throws(
+    () => generateBetween(new Date(0), end, 2),
+    /Invalid timestamp/
+  );
+});
+
+test("batchesOverlap() with intersecting spans", () => {
+  const a = [at(1700000010), at(1700000000), at(1700000005)];
+  const b = [at(1700000008), at(1700000020)];
+
+  assert.ok(batchesOverlap(a, b));
+  assert.ok(batchesOverlap(b, a));
+  assert.ok(batchesOverlap(a, [at(1700000003)]));
+  assert.ok(batchesOverlap(a, [at(1700000010, 0xff), at(1700000015)]));
+});
+
+test("batchesOverlap() with disjoint spans", () => {
+  const a = [at(1700000000), at(1700000010)];
+  const b = [at(1700000011), at(1700000020)];
+
+  assert.not.ok(batchesOverlap(a, b));
+  assert.not.ok(batchesOverlap(b, a));
+});
+
+test("batchesOverlap() with empty batches", () => {
+  const a = [at(1700000000)];
+  assert.not.ok(batchesOverlap(a, []));
+  assert.not.ok(batchesOverlap([], a));
+  assert.not.ok(batchesOverlap([], []));
+});
+
+test.run();
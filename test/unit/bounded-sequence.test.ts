@@ -0,0 +1,71 @@
+import { test } from "uvu";
+import * as assert from "uvu/assert";
+import { BoundedSequence } from "../../src/bounded-sequence";
+import { KSUID } from "../../src/ksuid";
+import { isKSUIDError, KSUID_ERROR_CODES } from "../../src/errors";
+import { Buffer } from "buffer";
+
+test("next() returns exactly count ordered KSUIDs", () => {
+  const seed = KSUID.random();
+  const seq = new BoundedSequence({ seed, count: 5 });
+
+  const ids: KSUID[] = [];
+  while (seq.remaining() > 0) {
+    ids.push(seq.next());
+  }
+
+  assert.is(ids.length, 5);
+  assert.is(ids[0].compare(seed), 0);
+  for (let i = 1; i < ids.length; i++) {
+    assert.is(ids[i].compare(ids[i - 1].next()), 0);
+    assert.is(ids[i].timestamp, seed.timestamp);
+  }
+});
+
+test("next() throws SEQUENCE_EXHAUSTED after count calls", () => {
+  const seq = new BoundedSequence({ seed: KSUID.random(), count: 2 });
+  seq.next();
+  seq.next();
+  assert.is(seq.remaining(), 0);
+
+  try {
+    seq.next();
+    assert.unreachable("should have thrown");
+  } catch (error) {
+    assert.ok(isKSUIDError(error));
+    assert.is(error.code, KSUID_ERROR_CODES.SEQUENCE_EXHAUSTED);
+  }
+});
+
+test("remaining() counts down", () => {
+  const seq = new BoundedSequence({ seed: KSUID.random(), count: 3 });
+  assert.is(seq.remaining(), 3);
+  seq.next();
+  assert.is(seq.remaining(), 2);
+
+  const empty = new BoundedSequence({ seed: KSUID.random(), count: 0 });
+  assert.is(empty.remaining(), 0);
+  assert.throws(() => empty.next(), /Sequence exhausted/);
+});
+
+test("constructor rejects blocks overflowing into the next second", () => {
+  const payload = Buffer.alloc(16, 0xff);
+  payload[15] = 0xfd;
+  const seed = KSUID.fromParts(95004740, payload);
+
+  const seq = new BoundedSequence({ seed, count: 3 });
+  assert.is(seq.next().timestamp, 95004740);
+  assert.is(seq.next().timestamp, 95004740);
+  assert.is(seq.next().payload.toString("hex"), "ff".repeat(16));
+
+  assert.throws(
+    () => new BoundedSequence({ seed, count: 4 }),
+    /overflow into the next second/
+  );
+  assert.throws(
+    () => new BoundedSequence({ seed, count: -1 }),
+    /Invalid count/
+  );
+});
+
+test.run();
@@ -0,0 +1,407 @@
+import { test } from "uvu";
+import * as assert from "uvu/assert";
+import { spawn } from "child_process";
+import { KSUID } from "../../src/ksuid";
+
+interface CLIResult {
+  stdout: string;
+  stdoutBytes: Buffer;
+  stderr: string;
+  exitCode: number;
+}
+
+async function runCLI(
+  args: string[],
+  input: string | Buffer = ""
+): Promise<CLIResult> {
+  return new Promise((resolve, reject) => {
+    const child = spawn(
+      "node",
+      ["-r", "ts-node/register", "src/cli.ts", ...args],
+      {
+        stdio: "pipe",
+        cwd: process.cwd(),
+      }
+    );
+
+    const chunks: Buffer[] = [];
+    let stderr = "";
+
+    child.stdout?.on("data", data => {
+      chunks.push(data);
+    });
+
+    child.stderr?.on("data", data => {
+      stderr += data.toString();
+    });
+
+    child.on("close", code => {
+      const stdoutBytes = Buffer.concat(chunks);
+      resolve({
+        stdout: stdoutBytes.toString(),
+        stdoutBytes,
+        stderr,
+        exitCode: code || 0,
+      });
+    });
+
+    child.on("error", reject);
+
+    child.stdin?.end(input);
+  });
+}
+
+const valid = "0o5sKzFDBc56T8mbUP8wH1KpSX7";
+
+test("validate: all valid arguments exit 0 silently", async () => {
+  const result = await runCLI([
+    "validate",
+    valid,
+    "000000000000000000000000000",
+  ]);
+  assert.is(result.exitCode, 0);
+  assert.is(result.stdout, "");
+  assert.is(result.stderr, "");
+});
+
+test("validate: invalid arguments are reported on stderr", async () => {
+  const result = await runCLI(["validate", valid, "not-a-ksuid"]);
+  assert.is(result.exitCode, 1);
+  assert.is(result.stdout, "");
+  assert.is(result.stderr.trim(), "not-a-ksuid");
+});
+
+test("validate: non-canonical overflow strings are rejected", async () => {
+  const result = await runCLI(["validate", "zzzzzzzzzzzzzzzzzzzzzzzzzzz"]);
+  assert.is(result.exitCode, 1);
+  assert.is(result.stderr.trim(), "zzzzzzzzzzzzzzzzzzzzzzzzzzz");
+});
+
+test("validate: reads stdin when no arguments are given", async () => {
+  const ok = await runCLI(["validate"], `${valid}\n${valid}\n`);
+  assert.is(ok.exitCode, 0);
+
+  const bad = await runCLI(["validate"], `${valid}\nbogus\n`);
+  assert.is(bad.exitCode, 1);
+  assert.is(bad.stderr.trim(), "bogus");
+});
+
+test("validate: dash-prefixed operands are reported as invalid", async () => {
+  const result = await runCLI(["validate", "-garbage"]);
+  assert.is(result.exitCode, 1);
+  assert.is(result.stderr.trim(), "-garbage");
+
+  const ended = await runCLI(["validate", "--", valid, "--strict"]);
+  assert.is(ended.exitCode, 1);
+  assert.is(ended.stderr.trim(), "--strict");
+});
+
+test("validate: fails when there are no KSUIDs", async () => {
+  const result = await runCLI(["validate"]);
+  assert.is(result.exitCode, 1);
+  assert.is(result.stderr, "validate: no KSUIDs given\n");
+});
+
+const older = "0ujsswThIGTUYm2K8FjOOfXtY1K";
+const newer = "0ujtsYcgvSTl8PAuAdqWYSMnLOv";
+
+test("verify: reports only the first invalid value", async () => {
+  const result = await runCLI(["verify", valid, "bogus", "also-bogus"]);
+  assert.is(result.exitCode, 1);
+  assert.is(result.stdout, "");
+  assert.is(result.stderr, "bogus\n");
+
+  const ok = await runCLI(["verify"], `${valid}\n${valid}\n`);
+  assert.is(ok.exitCode, 0);
+  assert.is(ok.stderr, "");
+});
+
+test("verify: --quiet relies on the exit status alone", async () => {
+  const result = await runCLI(["verify", "--quiet"], "bogus\n");
+  assert.is(result.exitCode, 1);
+  assert.is(result.stdout, "");
+  assert.is(result.stderr, "");
+});
+
+test("verify: dash-prefixed operands and empty input fail", async () => {
+  const result = await runCLI(["verify", "-bad"]);
+  assert.is(result.exitCode, 1);
+  assert.is(result.stderr, "-bad\n");
+
+  const empty = await runCLI(["verify", "--quiet"]);
+  assert.is(empty.exitCode, 1);
+  assert.is(empty.stderr, "");
+});
+
+test("sort: orders stdin lines chronologically", async () => {
+  const result = await runCLI(["sort"], `${newer}\n${valid}\n${older}\n`);
+  assert.is(result.exitCode, 0);
+  assert.is(result.stdout, `${valid}\n${older}\n${newer}\n`);
+});
+
+test("sort: large output is not truncated through a pipe", async () => {
+  const ids = Array.from({ length: 5000 }, () => KSUID.random().toString());
+  const result = await runCLI(["sort"], ids.join("\n") + "\n");
+  assert.is(result.exitCode, 0);
+  assert.is(result.stdout.trim().split("\n").length, ids.length);
+});
+
+test("sort: -r and --reverse emit descending order", async () => {
+  const input = `${older}\n${newer}\n${valid}\n`;
+  const expected = `${newer}\n${older}\n${valid}\n`;
+
+  assert.is((await runCLI(["sort", "-r"], input)).stdout, expected);
+  assert.is((await runCLI(["sort", "--reverse"], input)).stdout, expected);
+});
+
+test("sort: invalid lines fail with their line number", async () => {
+  const result = await runCLI(["sort"], `${newer}\nbogus\n${older}\n`);
+  assert.is(result.exitCode, 1);
+  assert.is(result.stdout, "");
+  assert.match(result.stderr, /line 2: invalid KSUID "bogus"/);
+});
+
+test("sort: --skip-invalid drops invalid lines", async () => {
+  const result = await runCLI(
+    ["sort", "--skip-invalid"],
+    `${newer}\nbogus\n${older}\n`
+  );
+  assert.is(result.exitCode, 0);
+  assert.is(result.stdout, `${older}\n${newer}\n`);
+});
+
+test("sort: --unique drops duplicates", async () => {
+  const input = `${newer}\n${older}\n${newer}\n${older}\n${newer}\n`;
+  const result = await runCLI(["sort", "--unique"], input);
+  assert.is(result.exitCode, 0);
+  assert.is(result.stdout, `${older}\n${newer}\n`);
+  assert.is(result.stderr, "");
+
+  const verbose = await runCLI(["sort", "--unique", "-v", "-r"], input);
+  assert.is(verbose.stdout, `${newer}\n${older}\n`);
+  assert.is(verbose.stderr, "removed 3 duplicate KSUIDs\n");
+
+  const long = await runCLI(["sort", "--unique", "--verbose"], input);
+  assert.is(long.stdout, `${older}\n${newer}\n`);
+  assert.is(long.stderr, "removed 3 duplicate KSUIDs\n");
+});
+
+test("stats: summarizes stdin KSUIDs", async () => {
+  const input = `${newer}\n${older}\n\n${newer}\noops\n`;
+  const result = await runCLI(["stats"], input);
+  assert.is(result.exitCode, 0);
+  assert.is(
+    result.stdout,
+    [
+      "total:    3",
+      "distinct: 2",
+      "earliest: 2017-10-10T03:52:37.000Z",
+      "latest:   2017-10-10T04:00:47.000Z",
+      "span:     490s",
+      "invalid:  1",
+      "",
+    ].join("\n")
+  );
+});
+
+test("stats: empty input reports zero counts", async () => {
+  const result = await runCLI(["stats"], "");
+  assert.is(result.exitCode, 0);
+  assert.is(result.stdout, "total:    0\ndistinct: 0\ninvalid:  0\n");
+});
+
+test("-f hex and -f base64 print the raw bytes", async () => {
+  const hex = await runCLI(["-f", "hex", valid]);
+  assert.is(hex.stdout, "05a9a844669f7efd7b6fe812278486085878563d\n");
+
+  const base64 = await runCLI(["-f", "base64", valid]);
+  assert.is(base64.stdout, "BamoRGaffv17b+gSJ4SGCFh4Vj0=\n");
+});
+
+test("-f raw concatenates 20 bytes per KSUID without separators", async () => {
+  const result = await runCLI(["-f", "raw", valid, older]);
+  assert.is(result.exitCode, 0);
+  assert.is(
+    result.stdoutBytes.toString("hex"),
+    KSUID.parse(valid).toBuffer().toString("hex") +
+      KSUID.parse(older).toBuffer().toString("hex")
+  );
+
+  const generated = await runCLI(["-f", "raw", "-n", "50"]);
+  assert.is(generated.stdoutBytes.length, 50 * 20);
+});
+
+test("decode: prints one KSUID per 20 raw bytes", async () => {
+  const raw = Buffer.concat([
+    KSUID.parse(valid).toBuffer(),
+    KSUID.parse(older).toBuffer(),
+  ]);
+  const result = await runCLI(["decode"], raw);
+  assert.is(result.exitCode, 0);
+  assert.is(result.stdout, `${valid}\n${older}\n`);
+});
+
+test("decode: --hex reads hex text", async () => {
+  const hex = "05a9a844669f7efd7b6fe812278486085878563d";
+  const input = `${hex}\n${hex.toUpperCase()}\n`;
+  const result = await runCLI(["decode", "--hex"], input);
+  assert.is(result.exitCode, 0);
+  assert.is(result.stdout, `${valid}\n${valid}\n`);
+});
+
+test("decode: rejects partial KSUIDs and bad hex", async () => {
+  const partial = await runCLI(["decode"], Buffer.alloc(30));
+  assert.is(partial.exitCode, 1);
+  assert.is(partial.stdout, "");
+  assert.match(partial.stderr, /30 bytes, not a multiple of 20/);
+
+  const badHex = await runCLI(["decode", "--hex"], "zz\n");
+  assert.is(badHex.exitCode, 1);
+  assert.match(badHex.stderr, /not valid hex/);
+});
+
+test("hex and base64 arguments convert back to base62", async () => {
+  const fromHex = await runCLI(["05a9a844669f7efd7b6fe812278486085878563d"]);
+  assert.is(fromHex.stdout, `${valid}\n`);
+
+  const fromBase64 = await runCLI(["BamoRGaffv17b+gSJ4SGCFh4Vj0="]);
+  assert.is(fromBase64.stdout, `${valid}\n`);
+});
+
+test("--timestamp accepts Unix seconds and RFC 3339", async () => {
+  for (const value of ["1704067200", "2024-01-01T00:00:00Z"]) {
+    const result = await runCLI(["-n", "3", "--timestamp", value]);
+    assert.is(result.exitCode, 0);
+
+    const lines = result.stdout.trim().split("\n");
+    assert.is(lines.length, 3);
+    for (const line of lines) {
+      assert.is(KSUID.parse(line).timestamp, 1704067200 - 1400000000);
+    }
+    assert.is(new Set(lines).size, 3);
+  }
+});
+
+test("--timestamp rejects malformed or out-of-range times", async () => {
+  for (const value of ["yesterday", "1000", "9999999999"]) {
+    const result = await runCLI(["--timestamp", value]);
+    assert.is(result.exitCode, 1);
+    assert.is(result.stdout, "");
+    assert.match(result.stderr, /Invalid --timestamp/);
+  }
+});
+
+test("--payload with --timestamp rebuilds a known KSUID", async () => {
+  const result = await runCLI([
+    "--timestamp",
+    "1495004740",
+    "--payload",
+    "669f7efd7b6fe812278486085878563d",
+  ]);
+  assert.is(result.exitCode, 0);
+  assert.is(result.stdout, `${valid}\n`);
+});
+
+test("--payload alone uses the current time", async () => {
+  const payload = "00112233445566778899aabbccddeeff";
+  const result = await runCLI(["--payload", payload]);
+  assert.is(result.exitCode, 0);
+
+  const ksuid = KSUID.parse(result.stdout.trim());
+  assert.is(ksuid.payload.toString("hex"), payload);
+  const now = Math.floor(Date.now() / 1000) - 1400000000;
+  assert.ok(Math.abs(ksuid.timestamp - now) < 60);
+});
+
+test("--payload rejects malformed hex before generating", async () => {
+  for (const value of ["669f7efd", "669f7efd7b6fe812278486085878563g"]) {
+    const result = await runCLI(["--payload", value]);
+    assert.is(result.exitCode, 1);
+    assert.is(result.stdout, "");
+    assert.match(result.stderr, /Invalid --payload/);
+  }
+});
+
+test("-f json prints one inspect object per KSUID", async () => {
+  const result = await runCLI(["-f", "json", valid, older]);
+  assert.is(result.exitCode, 0);
+
+  const lines = result.stdout.trim().split("\n");
+  assert.is(lines.length, 2);
+  assert.equal(JSON.parse(lines[0]), {
+    ksuid: valid,
+    raw: "05A9A844669F7EFD7B6FE812278486085878563D",
+    time: "2017-05-17T07:05:40.000Z",
+    timestamp: 95004740,
+    payload: "669F7EFD7B6FE812278486085878563D",
+  });
+  assert.is(KSUID.fromInspectJSON(lines[1]).toString(), older);
+});
+
+test("-f json with -n emits newline-delimited JSON", async () => {
+  const result = await runCLI(["-f", "json", "-n", "3"]);
+  assert.is(result.exitCode, 0);
+
+  const lines = result.stdout.trim().split("\n");
+  assert.is(lines.length, 3);
+  for (const line of lines) {
+    const object = JSON.parse(line);
+    assert.is(KSUID.parse(object.ksuid).timestamp, object.timestamp);
+  }
+});
+
+test("inspect reads stdin when no KSUIDs are given", async () => {
+  const result = await runCLI(["-f", "inspect"], `${valid}\n\n${older}\n`);
+  assert.is(result.exitCode, 0);
+  assert.is(result.stderr, "");
+  assert.is(result.stdout.split("REPRESENTATION").length, 3);
+  assert.ok(result.stdout.includes(valid));
+  assert.ok(result.stdout.includes(older));
+});
+
+test("inspect reports invalid stdin lines and continues", async () => {
+  const input = `${valid}\nbogus\n${older}\n`;
+  const result = await runCLI(["-f", "inspect"], input);
+  assert.is(result.exitCode, 1);
+  assert.is(result.stderr, 'line 2: invalid KSUID "bogus"\n');
+  assert.ok(result.stdout.includes(valid));
+  assert.ok(result.stdout.includes(older));
+});
+
+test("inspect with -n generates instead of reading stdin", async () => {
+  const result = await runCLI(["-f", "inspect", "-n", "2"], `${valid}\n`);
+  assert.is(result.exitCode, 0);
+  assert.not.ok(result.stdout.includes(valid));
+  assert.is(result.stdout.split("REPRESENTATION").length, 3);
+});
+
+test("inspect --strict stops at the first invalid line", async () => {
+  const input = `${valid}\nbogus\n${older}\n`;
+  const result = await runCLI(["-f", "inspect", "--strict"], input);
+  assert.is(result.exitCode, 1);
+  assert.is(result.stderr, 'line 2: invalid KSUID "bogus"\n');
+  assert.ok(result.stdout.includes(valid));
+  assert.not.ok(result.stdout.includes(older));
+});
+
+test("inspect with empty stdin generates a KSUID", async () => {
+  const result = await runCLI(["-f", "inspect"]);
+  assert.is(result.exitCode, 0);
+  assert.is(result.stderr, "");
+  assert.ok(result.stdout.includes("REPRESENTATION"));
+  assert.match(result.stdout, /String: [0-9A-Za-z]{27}\n/);
+});
+
+test("-f json generates instead of reading stdin", async () => {
+  const result = await runCLI(["-f", "json"], `${valid}\n`);
+  assert.is(result.exitCode, 0);
+  assert.is.not(JSON.parse(result.stdout).ksuid, valid);
+});
+
+test("inspect reads stdin when - is given", async () => {
+  const result = await runCLI(["-f", "inspect", "-"], `${valid}\n`);
+  assert.is(result.exitCode, 0);
+  assert.ok(result.stdout.includes(valid));
+});
+
+test.run();
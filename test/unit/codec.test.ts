@@ -0,0 +1,146 @@
+import { test } from "uvu";
+import * as assert from "uvu/assert";
+import { KSUID } from "../../src/ksuid";
+import { KSUIDError } from "../../src/errors";
+import {
+  encodeColumnar,
+  decodeColumnar,
+  packKSUIDs,
+  unpackKSUIDs,
+  readKSUID,
+} from "../../src/codec";
+import { Buffer } from "buffer";
+import { PassThrough } from "stream";
+
+test("encodeColumnar()/decodeColumnar() round trip", () => {
+  const ids = Array.from({ length: 25 }, () => KSUID.random());
+  const { timestamps, payloads } = encodeColumnar(ids);
+
+  assert.is(timestamps.length, 25 * 4);
+  assert.is(payloads.length, 25 * 16);
+
+  const decoded = decodeColumnar(timestamps, payloads);
+  assert.is(decoded.length, ids.length);
+  for (let i = 0; i < ids.length; i++) {
+    assert.is(decoded[i].compare(ids[i]), 0);
+  }
+});
+
+test("encodeColumnar() separates timestamps from payloads", () => {
+  const ksuid = KSUID.parse("0o5sKzFDBc56T8mbUP8wH1KpSX7");
+  const { timestamps, payloads } = encodeColumnar([ksuid, ksuid]);
+
+  assert.is(timestamps.toString("hex"), "05a9a844".repeat(2));
+  assert.ok(payloads.equals(Buffer.concat([ksuid.payload, ksuid.payload])));
+});
+
+test("encodeColumnar()/decodeColumnar() with an empty batch", () => {
+  const { timestamps, payloads } = encodeColumnar([]);
+  assert.equal(decodeColumnar(timestamps, payloads), []);
+});
+
+test("decodeColumnar() rejects mismatched columns", () => {
+  const { timestamps, payloads } = encodeColumnar([
+    KSUID.random(),
+    KSUID.random(),
+  ]);
+
+  assert.throws(
+    () => decodeColumnar(timestamps.subarray(4), payloads),
+    /Malformed data/
+  );
+  assert.throws(
+    () => decodeColumnar(timestamps, payloads.subarray(1)),
+    /Malformed data/
+  );
+  assert.throws(
+    () => decodeColumnar(timestamps.subarray(1), payloads),
+    /Malformed data/
+  );
+});
+
+for (const n of [1, 2, 3]) {
+  test(`packKSUIDs()/unpackKSUIDs() round trip with ${n} KSUIDs`, () => {
+    const ids = Array.from({ length: n }, () => KSUID.random());
+    const packed = packKSUIDs(...ids);
+
+    assert.is(packed.length, 27 * n);
+    assert.match(packed, /^[0-9A-Za-z]+$/);
+
+    const unpacked = unpackKSUIDs(packed, n);
+    assert.is(unpacked.length, n);
+    unpacked.forEach((id, i) => assert.is(id.compare(ids[i]), 0));
+  });
+}
+
+test("packKSUIDs() of one KSUID is its string form", () => {
+  const ksuid = KSUID.parse("0o5sKzFDBc56T8mbUP8wH1KpSX7");
+  assert.is(packKSUIDs(ksuid), ksuid.toString());
+});
+
+test("packKSUIDs() preserves leading nil KSUIDs", () => {
+  const ids = [KSUID.nil, KSUID.nil, KSUID.random()];
+  const unpacked = unpackKSUIDs(packKSUIDs(...ids), 3);
+  assert.ok(unpacked[0].isNil());
+  assert.ok(unpacked[1].isNil());
+  assert.is(unpacked[2].compare(ids[2]), 0);
+});
+
+test("unpackKSUIDs() rejects malformed tokens", () => {
+  const packed = packKSUIDs(KSUID.random(), KSUID.random());
+
+  assert.throws(() => unpackKSUIDs(packed, 1), /expected 27 characters/);
+  assert.throws(() => unpackKSUIDs(packed, 3), /expected 81 characters/);
+  assert.throws(() => unpackKSUIDs("z".repeat(54), 2), /exceeds 40 bytes/);
+  assert.throws(
+    () => unpackKSUIDs(packed.slice(0, 53) + "-", 2),
+    /invalid character/
+  );
+  assert.throws(() => unpackKSUIDs(packed, -1), /Invalid KSUID count/);
+});
+
+test("writeTo()/readKSUID() round trip over a stream", async () => {
+  const ids = [KSUID.nil, KSUID.random(), KSUID.random()];
+  const stream = new PassThrough();
+  for (const id of ids) {
+    assert.is(id.writeTo(stream), 20);
+  }
+  stream.end();
+
+  for (const id of ids) {
+    const read = await readKSUID(stream);
+    assert.ok(read);
+    assert.is(read.compare(id), 0);
+  }
+  assert.is(await readKSUID(stream), null);
+});
+
+test("readKSUID() waits for bytes split across chunks", async () => {
+  const id = KSUID.parse("0o5sKzFDBc56T8mbUP8wH1KpSX7");
+  const stream = new PassThrough();
+  const pending = readKSUID(stream);
+
+  stream.write(id.toBuffer().subarray(0, 7));
+  setImmediate(() => stream.end(id.toBuffer().subarray(7)));
+
+  const read = await pending;
+  assert.is(read?.toString(), "0o5sKzFDBc56T8mbUP8wH1KpSX7");
+});
+
+test("readKSUID() rejects a truncated KSUID", async () => {
+  const stream = new PassThrough();
+  stream.end(Buffer.alloc(7));
+
+  try {
+    await readKSUID(stream);
+    assert.unreachable("should have thrown");
+  } catch (error) {
+    assert.instance(error, KSUIDError);
+    const err = error as KSUIDError;
+    assert.match(err.message, /^Unexpected end of stream/);
+    assert.is(err.code, "MALFORMED_DATA");
+    assert.is(err.actual, "7 bytes");
+  }
+});
+
+test.run();
@@ -0,0 +1,46 @@
+import { test } from "uvu";
+import * as assert from "uvu/assert";
+import { EpochGenerator } from "../../src/epoch-generator";
+import { Buffer } from "buffer";
+
+const launch = new Date("2024-01-01T00:00:00Z");
+
+test("fromParts() counts seconds from the custom epoch", () => {
+  const gen = new EpochGenerator({ epoch: launch });
+  const t = new Date("2024-01-01T01:00:00.750Z");
+  const id = gen.fromParts(t, Buffer.alloc(16));
+
+  assert.is(id.timestamp, 3600);
+  assert.is(gen.time(id).toISOString(), "2024-01-01T01:00:00.000Z");
+});
+
+test("random() round trips the current time through time()", () => {
+  const gen = new EpochGenerator({ epoch: launch });
+  const before = Math.floor(Date.now() / 1000) * 1000;
+  const id = gen.random();
+  const after = Date.now();
+
+  const time = gen.time(id).getTime();
+  assert.ok(time >= before && time <= after);
+  assert.is(id.timestamp, Math.floor(time / 1000) - 1704067200);
+});
+
+test("timestamps before the epoch are rejected", () => {
+  const gen = new EpochGenerator({ epoch: launch });
+  assert.throws(
+    () => gen.fromParts(new Date("2023-12-31T23:59:59Z"), Buffer.alloc(16)),
+    /Invalid timestamp/
+  );
+
+  const future = new EpochGenerator({ epoch: new Date(Date.now() + 60000) });
+  assert.throws(() => future.random(), /Invalid timestamp/);
+});
+
+test("constructor rejects invalid dates", () => {
+  assert.throws(
+    () => new EpochGenerator({ epoch: new Date("not a date") }),
+    /Invalid epoch/
+  );
+});
+
+test.run();
@@ -0,0 +1,36 @@
+import { test } from "uvu";
+import * as assert from "uvu/assert";
+import { KSUID } from "../../src/ksuid";
+import { Sequence } from "../../src/sequence";
+import { assertMonotonic, KSUIDGenerator } from "../../src/generator";
+
+test("assertMonotonic() accepts a Sequence", () => {
+  const seq = new Sequence({ seed: KSUID.random() });
+  assertMonotonic(seq, 1000);
+});
+
+test("assertMonotonic() rejects a non-monotonic generator", () => {
+  const ids = [KSUID.random(), KSUID.random()].sort((a, b) => a.compare(b));
+  let i = 0;
+  const descending: KSUIDGenerator = {
+    next: () => ids[1 - (i++ % 2)],
+  };
+
+  assert.throws(
+    () => assertMonotonic(descending, 5),
+    /not monotonic at index 1/
+  );
+});
+
+test("assertMonotonic() rejects repeated values", () => {
+  const ksuid = KSUID.random();
+  const constant: KSUIDGenerator = { next: () => ksuid };
+  assert.throws(() => assertMonotonic(constant, 2), /not monotonic/);
+});
+
+test("assertMonotonic() rejects an exhausted generator", () => {
+  const seq = new Sequence({ seed: KSUID.random() });
+  assert.throws(() => assertMonotonic(seq, 70000), /exhausted after 65536/);
+});
+
+test.run();
@@ -0,0 +1,94 @@
+import { test } from "uvu";
+import * as assert from "uvu/assert";
+import { KSUID } from "../../src/ksuid";
+import {
+  composeKey,
+  decomposeKey,
+  truncationCollisions,
+} from "../../src/key";
+import { Buffer } from "buffer";
+
+test("composeKey()/decomposeKey() round trip", () => {
+  const parent = KSUID.random();
+  const child = KSUID.random();
+
+  const key = composeKey(parent, child);
+  assert.is(key.length, 40);
+
+  const split = decomposeKey(key);
+  assert.is(split.parent.compare(parent), 0);
+  assert.is(split.child.compare(child), 0);
+});
+
+test("composite key order matches (parent, child) tuple order", () => {
+  const parents = Array.from({ length: 5 }, () => KSUID.random());
+  const pairs: [KSUID, KSUID][] = [];
+  for (const parent of parents) {
+    for (let i = 0; i < 5; i++) {
+      pairs.push([parent, KSUID.random()]);
+    }
+  }
+
+  const byTuple = [...pairs].sort(
+    (a, b) => a[0].compare(b[0]) || a[1].compare(b[1])
+  );
+  const byKey = [...pairs].sort((a, b) =>
+    Buffer.compare(composeKey(a[0], a[1]), composeKey(b[0], b[1]))
+  );
+
+  for (let i = 0; i < pairs.length; i++) {
+    assert.is(byKey[i][0].compare(byTuple[i][0]), 0);
+    assert.is(byKey[i][1].compare(byTuple[i][1]), 0);
+  }
+});
+
+test("decomposeKey() rejects keys of the wrong length", () => {
+  assert.throws(
+    () => decomposeKey(Buffer.alloc(39)),
+    /Invalid composite key: expected 40 bytes, got 39/
+  );
+  assert.throws(() => decomposeKey(Buffer.alloc(20)));
+});
+
+test("key96() keeps the timestamp and high payload bytes", () => {
+  const ksuid = KSUID.parse("0o5sKzFDBc56T8mbUP8wH1KpSX7");
+  const key = ksuid.key96();
+  assert.is(key.length, 12);
+  assert.is(key.toString("hex"), "05a9a844669f7efd7b6fe812");
+});
+
+test("key96() preserves time order", () => {
+  const ids = Array.from({ length: 50 }, (_, i) =>
+    KSUID.fromParts(95004740 + i * 7, Buffer.from(KSUID.random().payload))
+  );
+  const shuffled = [...ids].sort(() => Math.random() - 0.5);
+  shuffled.sort((a, b) => Buffer.compare(a.key96(), b.key96()));
+
+  for (let i = 0; i < ids.length; i++) {
+    assert.is(shuffled[i].compare(ids[i]), 0);
+  }
+});
+
+test("truncationCollisions() finds IDs sharing a truncated prefix", () => {
+  const a = KSUID.fromParts(95004740, Buffer.alloc(16, 0x11));
+  const b = KSUID.fromParts(95004740, Buffer.alloc(16, 0x22));
+  const c = KSUID.fromParts(95004741, Buffer.alloc(16, 0x11));
+  const ids = [a, c, b, a];
+
+  assert.equal(truncationCollisions(ids, 4), [
+    [0, 2],
+    [0, 3],
+    [2, 3],
+  ]);
+  assert.equal(truncationCollisions(ids, 12), [[0, 3]]);
+  assert.equal(truncationCollisions(ids, 20), [[0, 3]]);
+  assert.equal(truncationCollisions([a, b, c], 5), []);
+});
+
+test("truncationCollisions() rejects invalid key lengths", () => {
+  const ids = [KSUID.random()];
+  assert.throws(() => truncationCollisions(ids, 0), /Invalid key length/);
+  assert.throws(() => truncationCollisions(ids, 21), /Invalid key length/);
+});
+
+test.run();
@@ -0,0 +1,68 @@
+import { test } from "uvu";
+import * as assert from "uvu/assert";
+import { KSUID } from "../../src/ksuid";
+import { Buffer } from "buffer";
+
+const payloadA = Buffer.from("669f7efd7b6fe812278486085878563d", "hex");
+const payloadB = Buffer.from("669f7efd7b6fe812278486085878563e", "hex");
+
+test("compareDetailed() with same-second pair", () => {
+  const a = KSUID.fromParts(95004740, payloadA);
+  const b = KSUID.fromParts(95004740, payloadB);
+
+  assert.equal(a.compareDetailed(b), { result: -1, sameSecond: true });
+  assert.equal(b.compareDetailed(a), { result: 1, sameSecond: true });
+});
+
+test("compareDetailed() with cross-second pair", () => {
+  const a = KSUID.fromParts(95004740, payloadB);
+  const b = KSUID.fromParts(95004741, payloadA);
+
+  assert.equal(a.compareDetailed(b), { result: -1, sameSecond: false });
+  assert.equal(b.compareDetailed(a), { result: 1, sameSecond: false });
+});
+
+test("compareDetailed() with equal KSUIDs", () => {
+  const a = KSUID.fromParts(95004740, payloadA);
+  const b = KSUID.fromParts(95004740, payloadA);
+
+  assert.equal(a.compareDetailed(b), { result: 0, sameSecond: true });
+});
+
+test("compareDetailed() result agrees with compare()", () => {
+  for (let i = 0; i < 20; i++) {
+    const a = KSUID.random();
+    const b = KSUID.random();
+    assert.is(a.compareDetailed(b).result, a.compare(b));
+  }
+});
+
+test("before()/after()/equals() agree with compare()", () => {
+  const a = KSUID.fromParts(95004740, payloadA);
+  const b = KSUID.fromParts(95004740, payloadB);
+  const c = KSUID.fromParts(95004741, payloadA);
+
+  assert.ok(a.before(b));
+  assert.ok(b.before(c));
+  assert.not.ok(b.before(a));
+  assert.not.ok(a.before(a));
+
+  assert.ok(c.after(a));
+  assert.not.ok(a.after(b));
+  assert.not.ok(a.after(a));
+
+  assert.ok(a.equals(KSUID.fromParts(95004740, payloadA)));
+  assert.not.ok(a.equals(b));
+});
+
+test("sameTime() ignores the payload", () => {
+  const a = KSUID.fromParts(95004740, payloadA);
+  const b = KSUID.fromParts(95004740, payloadB);
+  const c = KSUID.fromParts(95004741, payloadA);
+
+  assert.ok(a.sameTime(b));
+  assert.ok(a.sameTime(a));
+  assert.not.ok(a.sameTime(c));
+});
+
+test.run();
@@ -1,7 +1,9 @@
 import { test } from "uvu";
 import * as assert from "uvu/assert";
 import { KSUID } from "../../src/ksuid";
+import { KSUIDError } from "../../src/errors";
 import { Buffer } from "buffer";
+import * as v8 from "v8";
 
 test("KSUID.parseOrNil with valid KSUID", () => {
   const validKsuid = "0o5sKzFDBc56T8mbUP8wH1KpSX7";
@@ -135,4 +137,514 @@ test("OrNil methods preserve original behavior on success", () => {
   assert.is(fromBytes.compare(fromBytesOrNil), 0);
 });
 
+test("KSUID.parseAny with base62 input", () => {
+  const ksuid = KSUID.parseAny("0o5sKzFDBc56T8mbUP8wH1KpSX7");
+  assert.is(ksuid.toString(), "0o5sKzFDBc56T8mbUP8wH1KpSX7");
+});
+
+test("KSUID.parseAny with hex input", () => {
+  const hex = "05a9a844669f7efd7b6fe812278486085878563d";
+
+  const lower = KSUID.parseAny(hex);
+  assert.is(lower.toString(), "0o5sKzFDBc56T8mbUP8wH1KpSX7");
+
+  const upper = KSUID.parseAny(hex.toUpperCase());
+  assert.is(upper.compare(lower), 0);
+});
+
+test("KSUID.parseAny with base64 input", () => {
+  const base64 = "BamoRGaffv17b+gSJ4SGCFh4Vj0=";
+  const ksuid = KSUID.parseAny(base64);
+
+  assert.is(ksuid.toString(), "0o5sKzFDBc56T8mbUP8wH1KpSX7");
+  assert.is(ksuid.toBuffer().toString("base64"), base64);
+});
+
+test("KSUID.parseAny rejects non-canonical base64", () => {
+  assert.throws(
+    () => KSUID.parseAny("BamoRGaffv17b+gSJ4SGCFh4Vj1="),
+    /not canonical base64/
+  );
+  assert.throws(
+    () => KSUID.parseAny("BamoRGaffv17b-gSJ4SGCFh4Vj0="),
+    /invalid character '-' at position 13/
+  );
+  assert.throws(
+    () => KSUID.parseAny("BamoRGaffv17b+gSJ4SGCFh4Vj0A"),
+    /not canonical base64/
+  );
+});
+
+test("KSUID.parseAny rejects invalid input", () => {
+  assert.throws(() => KSUID.parseAny("0o5sKzFDBc56T8mbUP8wH1KpSX"), /27.*40/);
+  assert.throws(() => KSUID.parseAny(""), /27.*40/);
+  assert.throws(
+    () => KSUID.parseAny("05a9a844669f7efd7b6fe812278486085878563g"),
+    /invalid character 'g' at position 39/
+  );
+});
+
+test("KSUID.cursor round trip", () => {
+  const ksuid = KSUID.random();
+  const cursor = ksuid.cursor();
+
+  assert.is(KSUID.parseCursor(cursor).compare(ksuid), 0);
+  assert.is(KSUID.parseCursor(KSUID.nil.cursor()).compare(KSUID.nil), 0);
+});
+
+test("KSUID.cursor is URL-safe", () => {
+  for (let i = 0; i < 20; i++) {
+    const cursor = KSUID.random().cursor();
+    assert.is(encodeURIComponent(cursor), cursor);
+    assert.ok(/^[0-9A-Za-z]+$/.test(cursor));
+  }
+});
+
+test("KSUID.parseCursor rejects invalid cursors", () => {
+  const ksuid = "0o5sKzFDBc56T8mbUP8wH1KpSX7";
+  assert.throws(() => KSUID.parseCursor(ksuid), /expected 28 characters/);
+  assert.throws(() => KSUID.parseCursor("9" + ksuid), /unsupported version/);
+  assert.throws(() => KSUID.parseCursor("1" + ksuid.slice(1) + "!"));
+});
+
+test("KSUID.filename round trip", () => {
+  const ksuid = KSUID.parse("0o5sKzFDBc56T8mbUP8wH1KpSX7");
+
+  assert.is(ksuid.filename(), "0o5sKzFDBc56T8mbUP8wH1KpSX7");
+  assert.is(ksuid.filename(".json"), "0o5sKzFDBc56T8mbUP8wH1KpSX7.json");
+  assert.is(ksuid.filename("json"), "0o5sKzFDBc56T8mbUP8wH1KpSX7.json");
+
+  for (const ext of ["", ".json", "txt", ".tar.gz"]) {
+    const parsed = KSUID.parseFilename(ksuid.filename(ext));
+    assert.is(parsed.compare(ksuid), 0);
+  }
+});
+
+test("KSUID.parseFilename rejects invalid stems", () => {
+  assert.throws(() => KSUID.parseFilename("not-a-ksuid.json"));
+  assert.throws(() => KSUID.parseFilename(".json"));
+  assert.throws(() => KSUID.parseFilename("0o5sKzFDBc56T8mbUP8wH1KpSX.json"));
+});
+
+test("KSUID.payloadUUID round trip", () => {
+  const ksuid = KSUID.parse("0o5sKzFDBc56T8mbUP8wH1KpSX7");
+  const uuid = ksuid.payloadUUID();
+  assert.is(uuid, "669f7efd-7b6f-e812-2784-86085878563d");
+
+  const time = new Date((ksuid.timestamp + 1400000000) * 1000);
+  const rebuilt = KSUID.fromPayloadUUID(uuid, time);
+  assert.is(rebuilt.compare(ksuid), 0);
+
+  const upper = KSUID.fromPayloadUUID(uuid.toUpperCase(), time);
+  assert.is(upper.compare(ksuid), 0);
+});
+
+test("KSUID.fromPayloadUUID rejects malformed UUIDs", () => {
+  const now = new Date();
+  assert.throws(() => KSUID.fromPayloadUUID("not-a-uuid", now), /Invalid UUID/);
+  assert.throws(
+    () => KSUID.fromPayloadUUID("669f7efd7b6fe812278486085878563d", now),
+    /Invalid UUID/
+  );
+  assert.throws(
+    () => KSUID.fromPayloadUUID("669f7efd-7b6f-e812-2784-86085878563g", now),
+    /Invalid UUID/
+  );
+});
+
+test("KSUID.minWithPrefix/maxWithPrefix bound the prefix range", () => {
+  for (let i = 0; i < 20; i++) {
+    const sample = KSUID.random();
+    const prefix = sample.toString().slice(0, 6);
+
+    const min = KSUID.minWithPrefix(prefix);
+    const max = KSUID.maxWithPrefix(prefix);
+
+    assert.ok(min.toString().startsWith(prefix));
+    assert.ok(max.toString().startsWith(prefix));
+    assert.ok(min.compare(sample) <= 0);
+    assert.ok(max.compare(sample) >= 0);
+    assert.not.ok(min.prev().toString().startsWith(prefix));
+    assert.not.ok(max.next().toString().startsWith(prefix));
+
+    for (let k = min, j = 0; j < 100; j++, k = k.next()) {
+      assert.ok(k.toString().startsWith(prefix));
+    }
+  }
+});
+
+test("KSUID.minWithPrefix/maxWithPrefix edge cases", () => {
+  assert.is(KSUID.minWithPrefix("").compare(KSUID.nil), 0);
+  const max = "aWgEPTl1tmebfsQzFP4bxwgy80V";
+  assert.is(KSUID.maxWithPrefix("").toString(), max);
+  assert.is(KSUID.maxWithPrefix("aWg").toString(), max);
+
+  const full = "0o5sKzFDBc56T8mbUP8wH1KpSX7";
+  assert.is(KSUID.minWithPrefix(full).toString(), full);
+  assert.is(KSUID.maxWithPrefix(full).toString(), full);
+});
+
+test("KSUID.minWithPrefix/maxWithPrefix reject invalid prefixes", () => {
+  assert.throws(() => KSUID.minWithPrefix("0o5s-"), /invalid character/);
+  assert.throws(() => KSUID.maxWithPrefix("0".repeat(28)), /at most 27/);
+  assert.throws(() => KSUID.minWithPrefix("b"), /no KSUID starts with/);
+  assert.throws(() => KSUID.maxWithPrefix("z"), /no KSUID starts with/);
+});
+
+test("KSUID.toBase32Hex round trip", () => {
+  const ksuid = KSUID.parse("0o5sKzFDBc56T8mbUP8wH1KpSX7");
+  const encoded = ksuid.toBase32Hex();
+  assert.is(encoded, "0MKQGH36JTVFQURFT092F14611C7GLHT");
+  assert.is(KSUID.parseBase32Hex(encoded).compare(ksuid), 0);
+  assert.is(KSUID.parseBase32Hex(encoded.toLowerCase()).compare(ksuid), 0);
+
+  assert.is(KSUID.nil.toBase32Hex(), "0".repeat(32));
+  const max = KSUID.fromBytes(Buffer.alloc(20, 0xff));
+  assert.is(max.toBase32Hex(), "V".repeat(32));
+  assert.is(KSUID.parseBase32Hex("V".repeat(32)).compare(max), 0);
+});
+
+test("KSUID.toBase32Hex preserves sort order", () => {
+  const ids = Array.from({ length: 50 }, () => KSUID.random());
+  const byValue = [...ids].sort((a, b) => a.compare(b));
+  const byString = [...ids].sort((a, b) =>
+    a.toBase32Hex() < b.toBase32Hex() ? -1 : 1
+  );
+  for (let i = 0; i < ids.length; i++) {
+    assert.is(byString[i].compare(byValue[i]), 0);
+  }
+});
+
+test("KSUID.parseBase32Hex rejects invalid input", () => {
+  assert.throws(() => KSUID.parseBase32Hex("0".repeat(31)), /32 characters/);
+  assert.throws(
+    () => KSUID.parseBase32Hex("0".repeat(31) + "W"),
+    /invalid character 'W' at position 31/
+  );
+});
+
+test("KSUID.fromInspectJSON reads inspect objects", () => {
+  const json = JSON.stringify({
+    string: "0o5sKzFDBc56T8mbUP8wH1KpSX7",
+    raw: "05A9A844669F7EFD7B6FE812278486085878563D",
+    time: "2017-05-17T07:05:40.000Z",
+    timestamp: 95004740,
+    payload: "669F7EFD7B6FE812278486085878563D",
+  });
+
+  const ksuid = KSUID.fromInspectJSON(json);
+  assert.is(ksuid.toString(), "0o5sKzFDBc56T8mbUP8wH1KpSX7");
+  assert.is(KSUID.fromInspectJSON(Buffer.from(json)).compare(ksuid), 0);
+
+  const stringOnly = JSON.stringify({ string: ksuid.toString() });
+  assert.is(KSUID.fromInspectJSON(stringOnly).compare(ksuid), 0);
+
+  const ksuidField = JSON.stringify({ ksuid: ksuid.toString() });
+  assert.is(KSUID.fromInspectJSON(ksuidField).compare(ksuid), 0);
+});
+
+test("KSUID.fromInspectJSON rejects inconsistent or malformed input", () => {
+  const inconsistent = JSON.stringify({
+    string: "0o5sKzFDBc56T8mbUP8wH1KpSX7",
+    raw: "0000000000000000000000000000000000000000",
+  });
+  assert.throws(() => KSUID.fromInspectJSON(inconsistent), /disagree/);
+  assert.throws(() => KSUID.fromInspectJSON("{"), /not valid JSON/);
+  assert.throws(
+    () => KSUID.fromInspectJSON("[]"),
+    /no "ksuid" or "string" field/
+  );
+  assert.throws(() => KSUID.fromInspectJSON("42"), /not a JSON object/);
+});
+
+test("KSUID.canonicalize returns canonical input unchanged", () => {
+  for (const s of [
+    "0o5sKzFDBc56T8mbUP8wH1KpSX7",
+    "000000000000000000000000000",
+    "aWgEPTl1tmebfsQzFP4bxwgy80V",
+  ]) {
+    assert.is(KSUID.canonicalize(s), s);
+  }
+});
+
+test("KSUID.canonicalize rejects values beyond 160 bits", () => {
+  assert.throws(
+    () => KSUID.canonicalize("aWgEPTl1tmebfsQzFP4bxwgy80W"),
+    /exceeds the maximum value/
+  );
+  assert.throws(
+    () => KSUID.canonicalize("zzzzzzzzzzzzzzzzzzzzzzzzzzz"),
+    /exceeds the maximum value/
+  );
+  assert.throws(() => KSUID.canonicalize("short"), /expected 27 characters/);
+});
+
+test("KSUID.marshalBinary/unmarshalBinary round trip", () => {
+  const ksuid = KSUID.parse("0o5sKzFDBc56T8mbUP8wH1KpSX7");
+  const bytes = ksuid.marshalBinary();
+
+  assert.is(bytes.toString("hex"), "05a9a844669f7efd7b6fe812278486085878563d");
+  assert.is(KSUID.unmarshalBinary(bytes).compare(ksuid), 0);
+  assert.is(KSUID.unmarshalBinary(new Uint8Array(bytes)).compare(ksuid), 0);
+
+  bytes.fill(0);
+  assert.is(ksuid.toString(), "0o5sKzFDBc56T8mbUP8wH1KpSX7");
+});
+
+test("KSUID.marshalBinary survives v8 serialization", () => {
+  const ids = [KSUID.random(), KSUID.nil, KSUID.random()];
+  const cached = v8.deserialize(
+    v8.serialize({ ids: ids.map(id => id.marshalBinary()) })
+  ) as { ids: Buffer[] };
+
+  const restored = cached.ids.map(b => KSUID.unmarshalBinary(b));
+  assert.equal(restored.map(id => id.toString()), ids.map(id => id.toString()));
+});
+
+test("KSUID.unmarshalBinary rejects other lengths", () => {
+  assert.throws(() => KSUID.unmarshalBinary(Buffer.alloc(19)), /expected 20/);
+  assert.throws(() => KSUID.unmarshalBinary(Buffer.alloc(21)), /expected 20/);
+});
+
+test("KSUID.marshalText/unmarshalText round trip", () => {
+  const ksuid = KSUID.parse("0o5sKzFDBc56T8mbUP8wH1KpSX7");
+  const text = ksuid.marshalText();
+
+  assert.is(text.toString("utf8"), "0o5sKzFDBc56T8mbUP8wH1KpSX7");
+  assert.is(KSUID.unmarshalText(text).compare(ksuid), 0);
+
+  const nil = KSUID.nil.marshalText();
+  assert.is(nil.toString("utf8"), "000000000000000000000000000");
+  assert.ok(KSUID.unmarshalText(nil).isNil());
+});
+
+test("KSUID.unmarshalText rejects malformed text like parse", () => {
+  const inputs = ["", "tooShort", "0o5sKzFDBc56T8mbUP8wH1KpSX!"];
+  for (const input of inputs) {
+    let expected = "";
+    try {
+      KSUID.parse(input);
+    } catch (error) {
+      expected = (error as Error).message;
+    }
+    assert.throws(
+      () => KSUID.unmarshalText(Buffer.from(input, "utf8")),
+      (error: Error) => error.message === expected
+    );
+  }
+});
+
+test("KSUID.value/scan round trip", () => {
+  const ksuid = KSUID.random();
+  const value = ksuid.value();
+
+  assert.is(value, ksuid.toString());
+  assert.is(KSUID.scan(value).compare(ksuid), 0);
+  assert.is(KSUID.scan(Buffer.from(value, "utf8")).compare(ksuid), 0);
+  assert.is(KSUID.scan(ksuid.toBuffer()).compare(ksuid), 0);
+  assert.is(KSUID.scan(new Uint8Array(ksuid.toBuffer())).compare(ksuid), 0);
+});
+
+test("KSUID.scan of null is nil", () => {
+  assert.ok(KSUID.scan(null).isNil());
+  assert.ok(KSUID.scan(undefined).isNil());
+});
+
+test("KSUID.scan rejects invalid sources", () => {
+  assert.throws(() => KSUID.scan(Buffer.alloc(19)), /got 19 bytes/);
+  assert.throws(() => KSUID.scan("not-a-ksuid"), /Invalid KSUID string/);
+  assert.throws(
+    () => KSUID.scan(42 as unknown as string),
+    /unsupported type number/
+  );
+});
+
+test("JSON.stringify() writes a KSUID field as a string", () => {
+  const ksuid = KSUID.parse("0o5sKzFDBc56T8mbUP8wH1KpSX7");
+  const json = JSON.stringify({ id: ksuid });
+
+  assert.is(json, '{"id":"0o5sKzFDBc56T8mbUP8wH1KpSX7"}');
+
+  const decoded = JSON.parse(json);
+  assert.is(KSUID.fromJSON(decoded.id).compare(ksuid), 0);
+});
+
+test("KSUID.fromJSON of null is nil", () => {
+  assert.ok(KSUID.fromJSON(null).isNil());
+  assert.ok(KSUID.fromJSON(JSON.parse(JSON.stringify(KSUID.nil))).isNil());
+});
+
+test("KSUID.fromJSON rejects non-string tokens", () => {
+  assert.throws(() => KSUID.fromJSON(42), /expected a string, got number/);
+  assert.throws(() => KSUID.fromJSON([1, 2]), /expected a string, got array/);
+  assert.throws(() => KSUID.fromJSON({}), /expected a string, got object/);
+  assert.throws(() => KSUID.fromJSON("bogus"), /Invalid KSUID string/);
+});
+
+test("KSUID.minForTime/maxForTime bound a second", () => {
+  const t = new Date("2017-05-17T07:05:40.500Z");
+  const min = KSUID.minForTime(t);
+  const max = KSUID.maxForTime(t);
+
+  assert.is(min.timestamp, 95004740);
+  assert.ok(min.payload.equals(Buffer.alloc(16)));
+  assert.is(max.timestamp, 95004740);
+  assert.ok(max.payload.equals(Buffer.alloc(16, 0xff)));
+
+  const inside = KSUID.parse("0o5sKzFDBc56T8mbUP8wH1KpSX7");
+  assert.ok(min.compare(inside) <= 0 && inside.compare(max) <= 0);
+  const nextSecond = KSUID.minForTime(new Date(t.getTime() + 1000));
+  assert.is(max.next().compare(nextSecond), 0);
+});
+
+test("KSUID.minForTime/maxForTime clamp out-of-range times", () => {
+  assert.ok(KSUID.minForTime(new Date(0)).isNil());
+  assert.is(KSUID.maxForTime(new Date(0)).timestamp, 0);
+
+  const far = new Date((1400000000 + 2 ** 33) * 1000);
+  assert.is(KSUID.minForTime(far).timestamp, 0xffffffff);
+  assert.is(KSUID.maxForTime(far).toString(), "aWgEPTl1tmebfsQzFP4bxwgy80V");
+});
+
+test("KSUID.rangeForTime finds the IDs made in one second", () => {
+  // Roughly known creation time of the ID being looked for
+  const { min, max } = KSUID.rangeForTime(new Date("2017-05-17T07:05:40Z"));
+  assert.is(min.compare(KSUID.minForTime(new Date(1495004740000))), 0);
+  assert.is(max.compare(KSUID.maxForTime(new Date(1495004740000))), 0);
+
+  const stored = [
+    KSUID.parse("0o5sKzFDBc56T8mbUP8wH1KpSX7"),
+    KSUID.fromParts(95004739, Buffer.alloc(16, 0xff)),
+    KSUID.fromParts(95004740, Buffer.alloc(16)),
+    KSUID.fromParts(95004741, Buffer.alloc(16)),
+  ];
+  const found = stored.filter(
+    id => id.compare(min) >= 0 && id.compare(max) <= 0
+  );
+  assert.equal(
+    found.map(id => id.toString()),
+    [stored[0].toString(), stored[2].toString()]
+  );
+});
+
+test("KSUID.mustParse returns the parsed KSUID", () => {
+  const ksuid = KSUID.mustParse("0o5sKzFDBc56T8mbUP8wH1KpSX7");
+  assert.is(ksuid.timestamp, 95004740);
+  assert.is(ksuid.toString(), "0o5sKzFDBc56T8mbUP8wH1KpSX7");
+});
+
+test("KSUID.mustParse names the offending string", () => {
+  try {
+    KSUID.mustParse("0o5sKzFDBc56T8mbUP8wH1KpSX!");
+    assert.unreachable("should have thrown");
+  } catch (error) {
+    assert.instance(error, KSUIDError);
+    const err = error as KSUIDError;
+    assert.match(
+      err.message,
+      /^KSUID\.mustParse\("0o5sKzFDBc56T8mbUP8wH1KpSX!"\)/
+    );
+    assert.is(err.code, "INVALID_CHARACTER");
+    assert.is(err.input, "0o5sKzFDBc56T8mbUP8wH1KpSX!");
+    assert.instance(err.cause, KSUIDError);
+  }
+});
+
+test("KSUID.parseWithinWindow accepts KSUIDs inside the window", () => {
+  const s = "0o5sKzFDBc56T8mbUP8wH1KpSX7";
+  const created = new Date("2017-05-17T07:05:40Z");
+  const hour = 60 * 60 * 1000;
+
+  assert.is(KSUID.parseWithinWindow(s, created, 0).toString(), s);
+  const later = new Date(created.getTime() + hour);
+  assert.is(KSUID.parseWithinWindow(s, later, hour).timestamp, 95004740);
+  const earlier = new Date(created.getTime() - 5000);
+  assert.is(
+    KSUID.parseWithinWindow(s, earlier, hour, 5000).timestamp,
+    95004740
+  );
+});
+
+test("KSUID.parseWithinWindow rejects expired and future KSUIDs", () => {
+  const s = "0o5sKzFDBc56T8mbUP8wH1KpSX7";
+  const created = new Date("2017-05-17T07:05:40Z").getTime();
+  const hour = 60 * 60 * 1000;
+
+  const codeOf = (now: number, maxSkew?: number): string => {
+    try {
+      KSUID.parseWithinWindow(s, new Date(now), hour, maxSkew);
+      return "none";
+    } catch (error) {
+      return (error as KSUIDError).code;
+    }
+  };
+  assert.is(codeOf(created + hour + 1), "TIMESTAMP_EXPIRED");
+  assert.is(codeOf(created - 1), "TIMESTAMP_IN_FUTURE");
+  assert.is(codeOf(created - 5001, 5000), "TIMESTAMP_IN_FUTURE");
+  assert.is(codeOf(created - 5000, 5000), "none");
+  assert.throws(
+    () => KSUID.parseWithinWindow(s, new Date(created), -1),
+    /Invalid maxAge/
+  );
+  assert.throws(
+    () => KSUID.parseWithinWindow("bogus", new Date(created), hour),
+    /expected 27 characters/
+  );
+});
+
+test("KSUID.parseAll parses every string", () => {
+  const strs = [KSUID.random().toString(), "0o5sKzFDBc56T8mbUP8wH1KpSX7"];
+  const ids = KSUID.parseAll(strs);
+
+  assert.equal(ids.map(id => id.toString()), strs);
+  assert.equal(KSUID.parseAll([]), []);
+});
+
+test("KSUID.parseAll reports the first failing index", () => {
+  const strs = ["0o5sKzFDBc56T8mbUP8wH1KpSX7", "bogus", "also bogus"];
+  try {
+    KSUID.parseAll(strs);
+    assert.unreachable("should have thrown");
+  } catch (error) {
+    assert.instance(error, KSUIDError);
+    const err = error as KSUIDError;
+    assert.match(err.message, /^Invalid KSUID at index 1 \("bogus"\)/);
+    assert.is(err.code, "INVALID_LENGTH");
+    assert.is(err.input, "bogus");
+    assert.instance(err.cause, KSUIDError);
+  }
+});
+
+test("KSUID.parseAllOrNil substitutes nil for invalid entries", () => {
+  const ids = KSUID.parseAllOrNil(["0o5sKzFDBc56T8mbUP8wH1KpSX7", "bogus"]);
+  assert.is(ids.length, 2);
+  assert.is(ids[0].toString(), "0o5sKzFDBc56T8mbUP8wH1KpSX7");
+  assert.ok(ids[1].isNil());
+});
+
+test("KSUID.toUUID/fromUUID round trip", () => {
+  const ksuid = KSUID.parse("0o5sKzFDBc56T8mbUP8wH1KpSX7");
+  const uuid = ksuid.toUUID();
+
+  assert.is(uuid.toString("hex"), "669f7efd7b6fe812278486085878563d");
+  assert.is(uuid.toString("hex"), ksuid.payloadUUID().replace(/-/g, ""));
+
+  const time = new Date((ksuid.timestamp + 1400000000) * 1000);
+  assert.is(KSUID.fromUUID(uuid, time).compare(ksuid), 0);
+
+  uuid[0] ^= 0xff;
+  assert.is(ksuid.payload[0], 0x66);
+});
+
+test("KSUID.fromUUID defaults to the current time", () => {
+  const uuid = Buffer.from("669f7efd7b6fe812278486085878563d", "hex");
+  const before = Math.floor(Date.now() / 1000) - 1400000000;
+  const ksuid = KSUID.fromUUID(uuid);
+  const after = Math.floor(Date.now() / 1000) - 1400000000;
+
+  assert.ok(ksuid.payload.equals(uuid));
+  assert.ok(ksuid.timestamp >= before && ksuid.timestamp <= after);
+  assert.throws(() => KSUID.fromUUID(Buffer.alloc(15)), /expected 16 bytes/);
+});
+
 test.run();
@@ -0,0 +1,141 @@
+import { test } from "uvu";
+import * as assert from "uvu/assert";
+import { KSUID } from "../../src/ksuid";
+import { Buffer } from "buffer";
+
+const base = KSUID.parse("0o5sKzFDBc56T8mbUP8wH1KpSX7");
+
+test("sibling() is deterministic", () => {
+  for (let i = 0; i < 5; i++) {
+    assert.is(base.sibling(i).compare(base.sibling(i)), 0);
+  }
+  const reparsed = KSUID.parse(base.toString());
+  assert.is(reparsed.sibling(7).compare(base.sibling(7)), 0);
+});
+
+test("sibling() values are distinct and share the timestamp", () => {
+  const seen = new Set<string>([base.toString()]);
+  for (let i = 0; i < 100; i++) {
+    const sibling = base.sibling(i);
+    assert.is(sibling.timestamp, base.timestamp);
+    assert.not.ok(seen.has(sibling.toString()));
+    seen.add(sibling.toString());
+  }
+});
+
+test("sibling() rejects invalid indexes", () => {
+  assert.throws(() => base.sibling(-1), /Invalid sibling index/);
+  assert.throws(() => base.sibling(1.5), /Invalid sibling index/);
+  assert.throws(() => base.sibling(0x100000000), /Invalid sibling index/);
+});
+
+test("deltaFrom()/applyDelta() round trip for nearby KSUIDs", () => {
+  let k = base;
+  for (let i = 0; i < 300; i++) {
+    const delta = k.deltaFrom(base);
+    assert.ok(delta.length <= 2);
+    assert.is(KSUID.applyDelta(base, delta).compare(k), 0);
+    k = k.next();
+  }
+});
+
+test("deltaFrom()/applyDelta() round trip across the full range", () => {
+  const max = KSUID.fromBytes(Buffer.alloc(20, 0xff));
+  const delta = max.deltaFrom(KSUID.nil);
+  assert.is(delta.length, 20);
+  assert.is(KSUID.applyDelta(KSUID.nil, delta).compare(max), 0);
+
+  const random = KSUID.random();
+  const back = KSUID.applyDelta(KSUID.nil, random.deltaFrom(KSUID.nil));
+  assert.is(back.compare(random), 0);
+});
+
+test("deltaFrom() of equal KSUIDs is empty", () => {
+  const delta = base.deltaFrom(base);
+  assert.is(delta.length, 0);
+  assert.is(KSUID.applyDelta(base, delta).compare(base), 0);
+});
+
+test("deltaFrom() rejects a KSUID below the anchor", () => {
+  assert.throws(() => base.prev().deltaFrom(base), /less than the anchor/);
+});
+
+test("applyDelta() rejects oversized deltas and overflow", () => {
+  assert.throws(() => KSUID.applyDelta(base, Buffer.alloc(21)), /at most 20/);
+  const max = KSUID.fromBytes(Buffer.alloc(20, 0xff));
+  assert.throws(
+    () => KSUID.applyDelta(max, Buffer.from([1])),
+    /exceeds the maximum/
+  );
+});
+
+test("sameSecondDistinct() shares the timestamp with a new payload", () => {
+  const ksuid = KSUID.random();
+  for (let i = 0; i < 10; i++) {
+    const other = ksuid.sameSecondDistinct();
+    assert.is(other.timestamp, ksuid.timestamp);
+    assert.not.ok(other.payload.equals(ksuid.payload));
+    assert.is.not(other.compare(ksuid), 0);
+  }
+
+  const nil = KSUID.nil.sameSecondDistinct();
+  assert.is(nil.timestamp, 0);
+  assert.not.ok(nil.isNil());
+});
+
+test("withPayload() replaces only the payload", () => {
+  const max = base.withPayload(Buffer.alloc(16, 0xff));
+
+  assert.is(max.timestamp, base.timestamp);
+  assert.is(max.payload.toString("hex"), "ff".repeat(16));
+  assert.is(base.payload.toString("hex"), "669f7efd7b6fe812278486085878563d");
+  assert.is(max.next().timestamp, base.timestamp + 1);
+  assert.throws(() => base.withPayload(Buffer.alloc(15)), /expected 16/);
+});
+
+test("withTimestamp() replaces only the timestamp", () => {
+  const moved = base.withTimestamp(new Date("2024-01-01T00:00:00.500Z"));
+
+  assert.is(moved.timestamp, 1704067200 - 1400000000);
+  assert.ok(moved.payload.equals(base.payload));
+  assert.is(base.withTimestamp(new Date(0)).timestamp, 0);
+});
+
+const obfuscationKey = Buffer.from("0123456789abcdef0123456789abcdef");
+
+test("obfuscate()/deobfuscate() round trip with a fixed key", () => {
+  const obfuscated = base.obfuscate(obfuscationKey);
+  assert.is(obfuscated, "X3HfJrefrhCoQP46KTkdM1MWIvo");
+  assert.is(KSUID.deobfuscate(obfuscated, obfuscationKey).compare(base), 0);
+
+  assert.is(KSUID.nil.obfuscate(obfuscationKey), "Q5ILQRatrNB6aaRsiXb08rD266T");
+  for (let i = 0; i < 20; i++) {
+    const ksuid = KSUID.random();
+    const obfuscated = ksuid.obfuscate(obfuscationKey);
+    const restored = KSUID.deobfuscate(obfuscated, obfuscationKey);
+    assert.is(restored.compare(ksuid), 0);
+  }
+});
+
+test("obfuscate() hides ordering and depends on the key", () => {
+  const a = base.obfuscate(obfuscationKey);
+  const b = base.next().obfuscate(obfuscationKey);
+  assert.is.not(a.slice(0, 10), b.slice(0, 10));
+
+  const other = base.obfuscate(Buffer.from("another key"));
+  assert.is.not(other, a);
+  assert.is.not(
+    KSUID.deobfuscate(a, Buffer.from("another key")).compare(base),
+    0
+  );
+});
+
+test("obfuscate()/deobfuscate() reject invalid input", () => {
+  assert.throws(() => base.obfuscate(Buffer.alloc(0)), /must not be empty/);
+  assert.throws(
+    () => KSUID.deobfuscate("zzzzzzzzzzzzzzzzzzzzzzzzzzz", obfuscationKey),
+    /exceeds the maximum value/
+  );
+});
+
+test.run();
@@ -26,4 +26,72 @@ test("KSUID.fromParts() encode/decode diagnostic", () => {
   assert.ok(roundtrip.toBuffer().equals(ksuid.toBuffer()), "buffer round-trip");
 });
 
+test("KSUID.randomVersioned() round trips the version", () => {
+  for (const version of [0, 1, 7, 15]) {
+    for (let i = 0; i < 10; i++) {
+      const ksuid = KSUID.randomVersioned(version);
+      assert.is(ksuid.version(), version);
+      assert.is(KSUID.parse(ksuid.toString()).version(), version);
+    }
+  }
+});
+
+test("KSUID.randomVersioned() rejects out-of-range versions", () => {
+  assert.throws(() => KSUID.randomVersioned(-1), /Invalid version/);
+  assert.throws(() => KSUID.randomVersioned(16), /Invalid version/);
+  assert.throws(() => KSUID.randomVersioned(2.5), /Invalid version/);
+});
+
+test("KSUID.randomChecked() passes verifyChecksum()", () => {
+  for (let i = 0; i < 50; i++) {
+    const ksuid = KSUID.randomChecked();
+    assert.ok(ksuid.verifyChecksum());
+    assert.ok(KSUID.parse(ksuid.toString()).verifyChecksum());
+  }
+});
+
+test("verifyChecksum() detects single-bit payload corruption", () => {
+  const ksuid = KSUID.randomChecked();
+  for (let bit = 0; bit < 128; bit++) {
+    const payload = Buffer.from(ksuid.payload);
+    payload[bit >> 3] ^= 0x80 >> (bit & 7);
+    const corrupted = KSUID.fromParts(ksuid.timestamp, payload);
+    assert.not.ok(corrupted.verifyChecksum(), `bit ${bit} flip undetected`);
+  }
+});
+
+function counterSource(start: number): (size: number) => Buffer {
+  let next = start;
+  return size => Buffer.alloc(size, next++);
+}
+
+test("KSUID.randomWithReader() uses the supplied source", () => {
+  const before = Math.floor(Date.now() / 1000) - 1400000000;
+  const ksuid = KSUID.randomWithReader(counterSource(7));
+  const after = Math.floor(Date.now() / 1000) - 1400000000;
+
+  assert.ok(ksuid.payload.equals(Buffer.alloc(16, 7)));
+  assert.ok(ksuid.timestamp >= before && ksuid.timestamp <= after);
+});
+
+test("KSUID.randomWithReader() propagates source errors", () => {
+  const failing = (): never => {
+    throw new Error("entropy unavailable");
+  };
+  assert.throws(() => KSUID.randomWithReader(failing), /entropy unavailable/);
+  assert.throws(() => KSUID.randomWithReader(() => Buffer.alloc(8)));
+});
+
+test("KSUID.setRand() overrides the source of KSUID.random()", () => {
+  KSUID.setRand(counterSource(1));
+  try {
+    assert.ok(KSUID.random().payload.equals(Buffer.alloc(16, 1)));
+    assert.ok(KSUID.random().payload.equals(Buffer.alloc(16, 2)));
+  } finally {
+    KSUID.setRand(null);
+  }
+
+  assert.not.ok(KSUID.random().payload.equals(Buffer.alloc(16, 3)));
+});
+
 test.run();
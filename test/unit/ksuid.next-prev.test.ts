@@ -100,4 +100,122 @@ test("KSUID ordering with next/prev", () => {
   assert.is(prev.compare(next), -1);
 });
 
+test("KSUID.nextSecond() advances timestamp and keeps payload", () => {
+  const timestamp = 95004740;
+  const payload = Buffer.from("669f7efd7b6fe812278486085878563d", "hex");
+  const ksuid = KSUID.fromParts(timestamp, payload);
+
+  const next = ksuid.nextSecond();
+  assert.is(next.timestamp, timestamp + 1);
+  assert.ok(next.payload.equals(payload));
+  assert.is(ksuid.compare(next), -1);
+});
+
+test("KSUID.prevSecond() rewinds timestamp and keeps payload", () => {
+  const timestamp = 95004740;
+  const payload = Buffer.from("669f7efd7b6fe812278486085878563d", "hex");
+  const ksuid = KSUID.fromParts(timestamp, payload);
+
+  const prev = ksuid.prevSecond();
+  assert.is(prev.timestamp, timestamp - 1);
+  assert.ok(prev.payload.equals(payload));
+  assert.ok(prev.nextSecond().toBuffer().equals(ksuid.toBuffer()));
+});
+
+test("KSUID.nextSecond()/prevSecond() wrap at the timestamp bounds", () => {
+  const payload = Buffer.alloc(16, 0xab);
+
+  const max = KSUID.fromParts(0xffffffff, payload);
+  assert.is(max.nextSecond().timestamp, 0);
+  assert.ok(max.nextSecond().payload.equals(payload));
+
+  const min = KSUID.fromParts(0, payload);
+  assert.is(min.prevSecond().timestamp, 0xffffffff);
+  assert.ok(min.prevSecond().payload.equals(payload));
+});
+
+test("KSUID.alignTo() floors the timestamp relative to the KSUID epoch", () => {
+  const payload = Buffer.from("669f7efd7b6fe812278486085878563d", "hex");
+  const ksuid = KSUID.fromParts(95004740, payload);
+  const minute = 60 * 1000;
+
+  const cases: [number, number][] = [
+    [1000, 95004740],
+    [minute, 95004720],
+    [5 * minute, 95004600],
+    [6 * 60 * minute, 94996800],
+  ];
+  for (const [period, expected] of cases) {
+    const aligned = ksuid.alignTo(period);
+    assert.is(aligned.timestamp, expected);
+    assert.ok(aligned.payload.equals(payload));
+  }
+});
+
+test("KSUID.alignTo() with periods under a second is a no-op", () => {
+  const ksuid = KSUID.random();
+  assert.is(ksuid.alignTo(0).compare(ksuid), 0);
+  assert.is(ksuid.alignTo(999).compare(ksuid), 0);
+  assert.is(ksuid.alignTo(-1000).compare(ksuid), 0);
+});
+
+test("KSUID.neighbors() returns prev and next", () => {
+  const payload = Buffer.from("669f7efd7b6fe812278486085878563d", "hex");
+  const ksuid = KSUID.fromParts(95004740, payload);
+
+  const { prev, next } = ksuid.neighbors();
+  assert.is(prev.next().compare(ksuid), 0);
+  assert.is(next.prev().compare(ksuid), 0);
+  assert.is(prev.compare(ksuid.prev()), 0);
+  assert.is(next.compare(ksuid.next()), 0);
+});
+
+test("KSUID.nextN()/prevN() match repeated next()/prev()", () => {
+  const ksuid = KSUID.random();
+  let stepped = ksuid;
+  for (let i = 1; i <= 10; i++) {
+    stepped = stepped.next();
+    assert.is(ksuid.nextN(BigInt(i)).compare(stepped), 0);
+    assert.is(stepped.prevN(BigInt(i)).compare(ksuid), 0);
+  }
+  assert.is(ksuid.nextN(0n).compare(ksuid), 0);
+  assert.is(ksuid.prevN(0n).compare(ksuid), 0);
+});
+
+test("KSUID.nextN()/prevN() carry across the payload boundary", () => {
+  const payload = Buffer.alloc(16, 0xff);
+  payload[15] = 0xfe;
+  const ksuid = KSUID.fromParts(95004740, payload);
+
+  const next = ksuid.nextN(3n);
+  assert.is(next.timestamp, 95004741);
+  assert.ok(next.payload.equals(Buffer.from("00".repeat(15) + "01", "hex")));
+  assert.is(next.compare(ksuid.next().next().next()), 0);
+  assert.is(next.prevN(3n).compare(ksuid), 0);
+
+  const far = ksuid.nextN(0xffffffffffffffffn);
+  assert.is(far.timestamp, 95004741);
+  assert.is(far.prevN(0xffffffffffffffffn).compare(ksuid), 0);
+});
+
+test("KSUID.next()/nextN() wrap the maximum KSUID to nil", () => {
+  const max = KSUID.fromBytes(Buffer.alloc(20, 0xff));
+  assert.ok(max.next().isNil());
+  assert.ok(max.nextN(1n).isNil());
+  assert.is(max.nextN(2n).compare(KSUID.nil.next()), 0);
+
+  assert.is(KSUID.nil.prev().compare(max), 0);
+  assert.is(KSUID.nil.prevN(1n).compare(max), 0);
+});
+
+test("KSUID.nextN()/prevN() reject non-uint64 counts", () => {
+  const ksuid = KSUID.random();
+  assert.throws(() => ksuid.nextN(-1n), /Invalid step count/);
+  assert.throws(() => ksuid.prevN(1n << 64n), /Invalid step count/);
+  assert.throws(
+    () => ksuid.nextN(1 as unknown as bigint),
+    /Invalid step count/
+  );
+});
+
 test.run();
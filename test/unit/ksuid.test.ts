@@ -1,6 +1,6 @@
 import { test } from "uvu";
 import * as assert from "uvu/assert";
-import { KSUID } from "../../src/ksuid";
+import { KSUID, KSUID_STRING_PATTERN } from "../../src/ksuid";
 import { Buffer } from "buffer";
 
 const EPOCH = 1400000000;
@@ -96,6 +96,13 @@ test("KSUID.isNil() returns true only for all-zero buffer", () => {
 
   const nonNil = KSUID.random();
   assert.not.ok(nonNil.isNil());
+
+  for (let i = 0; i < 20; i++) {
+    const bytes = Buffer.alloc(20);
+    bytes[i] = 0x01;
+    assert.not.ok(KSUID.fromBytes(bytes).isNil());
+  }
+  assert.ok(KSUID.fromBytes(Buffer.alloc(20)).isNil());
 });
 
 test("KSUID.toBuffer() round-trip matches parse().toBuffer()", () => {
@@ -104,4 +111,306 @@ test("KSUID.toBuffer() round-trip matches parse().toBuffer()", () => {
   assert.ok(parsed.toBuffer().equals(ksuid.toBuffer()));
 });
 
+test("KSUID.secondFraction() spans [0, 1)", () => {
+  const zero = KSUID.fromParts(95004740, Buffer.alloc(16));
+  assert.is(zero.secondFraction(), 0);
+
+  const max = KSUID.fromParts(95004740, Buffer.alloc(16, 0xff));
+  assert.ok(max.secondFraction() < 1);
+  assert.ok(max.secondFraction() > 0.9999999);
+
+  const half = Buffer.alloc(16);
+  half[0] = 0x80;
+  assert.is(KSUID.fromParts(95004740, half).secondFraction(), 0.5);
+});
+
+test("KSUID.secondId() is shared within a second", () => {
+  const a = KSUID.fromParts(95004740, Buffer.alloc(16, 0x01));
+  const b = KSUID.fromParts(95004740, Buffer.alloc(16, 0xfe));
+  const c = KSUID.fromParts(95004741, Buffer.alloc(16, 0x01));
+
+  assert.is(a.secondId(), b.secondId());
+  assert.is.not(a.secondId(), c.secondId());
+  assert.is(a.secondId().length, 27);
+  assert.is(KSUID.parse(a.secondId()).timestamp, 95004740);
+  assert.ok(KSUID.parse(a.secondId()).payload.equals(Buffer.alloc(16)));
+});
+
+test("KSUID.timeShard() groups by window and payload", () => {
+  const payload = Buffer.from("669f7efd7b6fe812278486085878563d", "hex");
+  const hour = 60 * 60 * 1000;
+
+  // 2017-01-01T00:00:10Z and 2017-01-01T00:59:50Z
+  const a = KSUID.fromParts(1483228810 - EPOCH, payload);
+  const b = KSUID.fromParts(1483232390 - EPOCH, payload);
+
+  const sa = a.timeShard(hour, 16);
+  const sb = b.timeShard(hour, 16);
+  assert.is(sa.bucketTime.toISOString(), "2017-01-01T00:00:00.000Z");
+  assert.is(sa.bucketTime.getTime(), sb.bucketTime.getTime());
+  assert.is(sa.shard, sb.shard);
+  assert.ok(sa.shard >= 0 && sa.shard < 16);
+
+  const c = KSUID.fromParts(1483232410 - EPOCH, payload);
+  const sc = c.timeShard(hour, 16);
+  assert.is(sc.bucketTime.toISOString(), "2017-01-01T01:00:00.000Z");
+  assert.is(sc.shard, sa.shard);
+});
+
+test("KSUID.timeShard() rejects invalid arguments", () => {
+  const ksuid = KSUID.random();
+  assert.throws(() => ksuid.timeShard(0, 4), /Invalid window/);
+  assert.throws(() => ksuid.timeShard(1000, 0), /Invalid buckets/);
+  assert.throws(() => ksuid.timeShard(1000, 2.5), /Invalid buckets/);
+});
+
+test("KSUID.isPlausible() accepts a recent KSUID", () => {
+  const now = new Date((95004740 + EPOCH) * 1000);
+  const day = 24 * 60 * 60 * 1000;
+  const ksuid = KSUID.fromParts(95004740, Buffer.alloc(16));
+
+  assert.ok(ksuid.isPlausible(now, day, 365 * day));
+  assert.ok(ksuid.isPlausible(now, 0, 0));
+});
+
+test("KSUID.isPlausible() rejects implausible timestamps", () => {
+  const now = new Date((95004740 + EPOCH) * 1000);
+  const day = 24 * 60 * 60 * 1000;
+
+  const future = KSUID.fromParts(0xfffffff0, Buffer.alloc(16));
+  assert.not.ok(future.isPlausible(now, day, 365 * day));
+
+  const past = KSUID.fromParts(95004740 - 2 * 86400, Buffer.alloc(16));
+  assert.not.ok(past.isPlausible(now, day, day));
+  assert.ok(past.isPlausible(now, day, 3 * day));
+});
+
+test("KSUID.etag() is a stable quoted hash", () => {
+  const ksuid = KSUID.parse("0o5sKzFDBc56T8mbUP8wH1KpSX7");
+  assert.is(ksuid.etag(), '"e936e828c90746a9"');
+  assert.is(KSUID.parse(ksuid.toString()).etag(), ksuid.etag());
+  assert.ok(/^"[0-9a-f]{16}"$/.test(KSUID.random().etag()));
+  assert.is.not(ksuid.next().etag(), ksuid.etag());
+});
+
+test("KSUID.spokenCode() is stable and uses an unambiguous alphabet", () => {
+  const ksuid = KSUID.parse("0o5sKzFDBc56T8mbUP8wH1KpSX7");
+  assert.is(ksuid.spokenCode(), KSUID.parse(ksuid.toString()).spokenCode());
+
+  for (let i = 0; i < 50; i++) {
+    const code = KSUID.random().spokenCode();
+    assert.ok(/^[2-9A-HJKMNP-Z]{4}-[2-9A-HJKMNP-Z]{4}$/.test(code), code);
+  }
+});
+
+test("KSUID.avatarSeed() is stable and payload-specific", () => {
+  const ksuid = KSUID.parse("0o5sKzFDBc56T8mbUP8wH1KpSX7");
+  assert.is(
+    ksuid.avatarSeed(),
+    "43d3e3954a8a5f547a6acad788f0fc5a6f557cf81e1b73d0f9ede4500e635185"
+  );
+  assert.is(ksuid.nextSecond().avatarSeed(), ksuid.avatarSeed());
+  assert.is.not(ksuid.next().avatarSeed(), ksuid.avatarSeed());
+});
+
+test("KSUID.bloomHashes() is deterministic and distinct", () => {
+  const ksuid = KSUID.parse("0o5sKzFDBc56T8mbUP8wH1KpSX7");
+  const hashes = ksuid.bloomHashes(16);
+
+  assert.is(hashes.length, 16);
+  assert.equal(KSUID.parse(ksuid.toString()).bloomHashes(16), hashes);
+  assert.equal(ksuid.bloomHashes(4), hashes.slice(0, 4));
+  assert.is(new Set(hashes).size, hashes.length);
+  for (const hash of hashes) {
+    assert.ok(hash >= 0n && hash < 1n << 64n);
+  }
+  assert.is.not(ksuid.next().bloomHashes(1)[0], hashes[0]);
+});
+
+test("KSUID.bloomHashes() rejects invalid counts", () => {
+  const ksuid = KSUID.random();
+  assert.equal(ksuid.bloomHashes(0), []);
+  assert.throws(() => ksuid.bloomHashes(-1));
+  assert.throws(() => ksuid.bloomHashes(1.5));
+});
+
+test("KSUID_STRING_PATTERN matches canonical strings only", () => {
+  const pattern = new RegExp(KSUID_STRING_PATTERN);
+
+  assert.ok(pattern.test("0o5sKzFDBc56T8mbUP8wH1KpSX7"));
+  assert.ok(pattern.test("000000000000000000000000000"));
+  assert.ok(pattern.test("aWgEPTl1tmebfsQzFP4bxwgy80V"));
+  assert.ok(pattern.test(KSUID.random().toString()));
+
+  assert.not.ok(pattern.test(""));
+  assert.not.ok(pattern.test("0o5sKzFDBc56T8mbUP8wH1KpSX"));
+  assert.not.ok(pattern.test("0o5sKzFDBc56T8mbUP8wH1KpSX7a"));
+  assert.not.ok(pattern.test("0o5sKzFDBc56T8mbUP8wH1KpSX-"));
+  assert.not.ok(pattern.test(" 0o5sKzFDBc56T8mbUP8wH1KpSX7"));
+});
+
+test("KSUID.sinceEpoch() measures from the KSUID epoch", () => {
+  const ksuid = KSUID.parse("0o5sKzFDBc56T8mbUP8wH1KpSX7");
+  assert.is(ksuid.sinceEpoch(), 95004740000);
+  assert.is(
+    EPOCH * 1000 + ksuid.sinceEpoch(),
+    ksuid.timeRange().earliest.getTime()
+  );
+  assert.is(KSUID.nil.sinceEpoch(), 0);
+});
+
+test("KSUID.timeRange() is one second wide", () => {
+  const ksuid = KSUID.parse("0o5sKzFDBc56T8mbUP8wH1KpSX7");
+  const { earliest, latest } = ksuid.timeRange();
+
+  assert.is(earliest.toISOString(), "2017-05-17T07:05:40.000Z");
+  assert.is(latest.getTime() - earliest.getTime(), 1000);
+  assert.equal(ksuid.next().timeRange(), { earliest, latest });
+  assert.equal(ksuid.nextSecond().timeRange().earliest, latest);
+});
+
+test("KSUID.rendezvousNode() only remaps KSUIDs on a removed node", () => {
+  const nodes = ["node-a", "node-b", "node-c", "node-d", "node-e"];
+  const remaining = nodes.filter(node => node !== "node-c");
+  const ids = Array.from({ length: 1000 }, () => KSUID.random());
+
+  let moved = 0;
+  for (const id of ids) {
+    const before = id.rendezvousNode(nodes);
+    const after = id.rendezvousNode(remaining);
+    if (before === "node-c") {
+      moved++;
+      assert.is.not(after, "node-c");
+    } else {
+      assert.is(after, before);
+    }
+  }
+  assert.ok(moved > 100 && moved < 300);
+});
+
+test("KSUID.rendezvousNode() is stable and order-independent", () => {
+  const ksuid = KSUID.parse("0o5sKzFDBc56T8mbUP8wH1KpSX7");
+  const nodes = ["alpha", "beta", "gamma"];
+
+  const node = ksuid.rendezvousNode(nodes);
+  assert.is(ksuid.rendezvousNode([...nodes].reverse()), node);
+  assert.is(ksuid.nextSecond().rendezvousNode(nodes), node);
+  assert.is(ksuid.rendezvousNode(["only"]), "only");
+  assert.throws(() => ksuid.rendezvousNode([]), /at least one node/);
+});
+
+test("KSUID.appendString() matches toString()", () => {
+  const ids = [KSUID.nil, KSUID.parse("aWgEPTl1tmebfsQzFP4bxwgy80V")];
+  for (let i = 0; i < 100; i++) {
+    ids.push(KSUID.random());
+  }
+
+  const dst = Buffer.alloc(ids.length * 27);
+  let offset = 0;
+  for (const id of ids) {
+    offset = id.appendString(dst, offset);
+  }
+
+  assert.is(offset, dst.length);
+  assert.is(dst.toString("ascii"), ids.map(id => id.toString()).join(""));
+});
+
+test("KSUID.appendBytes() writes the raw bytes", () => {
+  const a = KSUID.random();
+  const b = KSUID.random();
+  const dst = Buffer.alloc(41);
+
+  assert.is(a.appendBytes(dst, 1), 21);
+  assert.is(b.appendBytes(dst, 21), 41);
+  assert.ok(dst.subarray(1, 21).equals(a.toBuffer()));
+  assert.ok(dst.subarray(21).equals(b.toBuffer()));
+  assert.is(dst[0], 0);
+});
+
+test("KSUID.appendString()/appendBytes() reject short buffers", () => {
+  const ksuid = KSUID.random();
+  assert.throws(() => ksuid.appendString(Buffer.alloc(26)), /need 27 bytes/);
+  assert.throws(() => ksuid.appendString(Buffer.alloc(30), 4), /offset 4/);
+  assert.throws(() => ksuid.appendBytes(Buffer.alloc(19)), /need 20 bytes/);
+});
+
+test("KSUID.isValid() accepts canonical strings", () => {
+  assert.ok(KSUID.isValid("0o5sKzFDBc56T8mbUP8wH1KpSX7"));
+  assert.ok(KSUID.isValid("000000000000000000000000000"));
+  assert.ok(KSUID.isValid("aWgEPTl1tmebfsQzFP4bxwgy80V"));
+  for (let i = 0; i < 100; i++) {
+    assert.ok(KSUID.isValid(KSUID.random().toString()));
+  }
+});
+
+test("KSUID.isValid() rejects malformed or oversized strings", () => {
+  assert.not.ok(KSUID.isValid(""));
+  assert.not.ok(KSUID.isValid("0o5sKzFDBc56T8mbUP8wH1KpSX"));
+  assert.not.ok(KSUID.isValid("0o5sKzFDBc56T8mbUP8wH1KpSX7a"));
+  assert.not.ok(KSUID.isValid("0o5sKzFDBc56T8mbUP8wH1KpSX-"));
+  assert.not.ok(KSUID.isValid("0o5sKzFDBc56T8mbUP8wH1KpS\u00e97"));
+  assert.not.ok(KSUID.isValid("aWgEPTl1tmebfsQzFP4bxwgy80W"));
+  assert.not.ok(KSUID.isValid("zzzzzzzzzzzzzzzzzzzzzzzzzzz"));
+  assert.not.ok(KSUID.isValid(null as unknown as string));
+});
+
+test("KSUID.compactString() strips leading zeros", () => {
+  const ksuid = KSUID.parse("0o5sKzFDBc56T8mbUP8wH1KpSX7");
+  assert.is(ksuid.compactString(), "o5sKzFDBc56T8mbUP8wH1KpSX7");
+  assert.is(KSUID.nil.compactString(), "0");
+  assert.is(KSUID.nil.next().compactString(), "1");
+
+  const max = "aWgEPTl1tmebfsQzFP4bxwgy80V";
+  assert.is(KSUID.parse(max).compactString(), max);
+});
+
+test("KSUID.parseCompact() round trips compactString()", () => {
+  for (const ksuid of [
+    KSUID.nil,
+    KSUID.nil.next(),
+    KSUID.parse("0o5sKzFDBc56T8mbUP8wH1KpSX7"),
+    KSUID.random(),
+  ]) {
+    const compact = ksuid.compactString();
+    assert.is(KSUID.parseCompact(compact).compare(ksuid), 0);
+  }
+  assert.is(
+    KSUID.parseCompact("0o5sKzFDBc56T8mbUP8wH1KpSX7").toString(),
+    "0o5sKzFDBc56T8mbUP8wH1KpSX7"
+  );
+});
+
+test("KSUID.parseCompact() rejects empty, long or invalid strings", () => {
+  assert.throws(() => KSUID.parseCompact(""), /1 to 27 characters, got 0/);
+  assert.throws(
+    () => KSUID.parseCompact("0o5sKzFDBc56T8mbUP8wH1KpSX7a"),
+    /1 to 27 characters, got 28/
+  );
+  assert.throws(() => KSUID.parseCompact("abc-"), /invalid character/);
+});
+
+test("KSUID.payload returns a copy", () => {
+  const ksuid = KSUID.parse("0o5sKzFDBc56T8mbUP8wH1KpSX7");
+  const payload = ksuid.payload;
+  payload.fill(0);
+
+  assert.is(ksuid.payload.toString("hex"), "669f7efd7b6fe812278486085878563d");
+  assert.is(ksuid.toString(), "0o5sKzFDBc56T8mbUP8wH1KpSX7");
+});
+
+test("KSUID.payloadInto() copies into a caller buffer", () => {
+  const ksuid = KSUID.parse("0o5sKzFDBc56T8mbUP8wH1KpSX7");
+  const dst = Buffer.alloc(34, 0xee);
+
+  assert.is(ksuid.payloadInto(dst, 1), 17);
+  assert.is(ksuid.payloadInto(dst, 17), 33);
+  assert.is(dst[0], 0xee);
+  assert.is(dst[33], 0xee);
+  assert.is(
+    dst.subarray(1, 33).toString("hex"),
+    "669f7efd7b6fe812278486085878563d".repeat(2)
+  );
+  assert.throws(() => ksuid.payloadInto(dst, 19), /16 bytes/);
+});
+
 test.run();
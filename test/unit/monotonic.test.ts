@@ -0,0 +1,106 @@
+import { test } from "uvu";
+import * as assert from "uvu/assert";
+import { MonotonicGenerator, stream } from "../../src/monotonic";
+import { KSUID } from "../../src/ksuid";
+import { assertMonotonic } from "../../src/generator";
+import { Buffer } from "buffer";
+
+const EPOCH = 1400000000;
+
+let now = 0;
+const clock = (): Date => new Date(now * 1000);
+
+test("next() is strictly increasing", () => {
+  const gen = new MonotonicGenerator();
+  assertMonotonic(gen, 10000);
+});
+
+test("next() increments the payload within a second", () => {
+  now = 1700000000;
+  const gen = new MonotonicGenerator({ clock });
+
+  const first = gen.next();
+  const second = gen.next();
+  assert.is(first.timestamp, 1700000000 - EPOCH);
+  assert.is(second.compare(first.next()), 0);
+});
+
+test("next() resumes random payloads when the second advances", () => {
+  now = 1700000000;
+  const gen = new MonotonicGenerator({ clock });
+
+  const first = gen.next();
+  gen.next();
+  now = 1700000001;
+  const later = gen.next();
+
+  assert.is(later.timestamp, 1700000001 - EPOCH);
+  assert.is(later.compare(first), 1);
+  assert.is.not(later.compare(first.nextSecond()), 0);
+});
+
+test("next() stays monotonic when the clock moves backwards", () => {
+  now = 1700000010;
+  const gen = new MonotonicGenerator({ clock });
+
+  const before = gen.next();
+  now = 1700000000;
+  const after = gen.next();
+
+  assert.is(after.compare(before), 1);
+  assert.is(after.compare(before.next()), 0);
+});
+
+test("stream() emits increasing KSUIDs until aborted", async () => {
+  const signal = { aborted: false };
+  const ids: KSUID[] = [];
+
+  for await (const id of stream(signal, { bufferSize: 10 })) {
+    ids.push(id);
+    if (ids.length === 25) {
+      signal.aborted = true;
+    }
+  }
+
+  // The batch in progress when aborting is drained, not dropped.
+  assert.is(ids.length, 30);
+  for (let i = 1; i < ids.length; i++) {
+    assert.is(ids[i].compare(ids[i - 1]), 1);
+  }
+});
+
+test("stream() ends immediately for an aborted signal", async () => {
+  let count = 0;
+  for await (const _ of stream({ aborted: true })) {
+    count++;
+  }
+  assert.is(count, 0);
+});
+
+test("stream() rejects an invalid buffer size", async () => {
+  try {
+    await stream(undefined, { bufferSize: 0 }).next();
+    assert.unreachable("should have thrown");
+  } catch (error) {
+    assert.match((error as Error).message, /Invalid buffer size/);
+  }
+});
+
+test("drift() grows when a stalled clock carries into later seconds", () => {
+  now = 1700000000;
+  const gen = new MonotonicGenerator({
+    clock,
+    random: size => Buffer.alloc(size, 0xff),
+  });
+  assert.is(gen.drift(clock()), 0);
+
+  const first = gen.next();
+  assert.is(gen.drift(clock()), 0);
+
+  const carried = gen.next();
+  gen.next();
+  assert.is(carried.timestamp, first.timestamp + 1);
+  assert.ok(gen.drift(clock()) > 0);
+});
+
+test.run();
@@ -0,0 +1,201 @@
+import { test } from "uvu";
+import * as assert from "uvu/assert";
+import { KSUID } from "../../src/ksuid";
+import {
+  interpolate,
+  estimateCount,
+  commonPrefixBits,
+  nthInRange,
+  between,
+  distance,
+  range,
+  ksuidToBigInt,
+} from "../../src/range";
+import { Buffer } from "buffer";
+
+const lo = KSUID.fromParts(95004740, Buffer.alloc(16));
+const hi = KSUID.fromParts(95004750, Buffer.alloc(16, 0xff));
+
+test("interpolate() includes both endpoints", () => {
+  const ids = interpolate(lo, hi, 5);
+  assert.is(ids.length, 5);
+  assert.is(ids[0].toString(), lo.toString());
+  assert.is(ids[4].toString(), hi.toString());
+});
+
+test("interpolate() produces evenly spaced values", () => {
+  const a = KSUID.fromParts(95004740, Buffer.alloc(16));
+  const b = KSUID.fromParts(
+    95004740,
+    Buffer.from("00".repeat(15) + "64", "hex")
+  );
+  const ids = interpolate(a, b, 5);
+
+  for (let i = 1; i < ids.length; i++) {
+    const step = ksuidToBigInt(ids[i]) - ksuidToBigInt(ids[i - 1]);
+    assert.is(step, 25n);
+  }
+});
+
+test("interpolate() spacing differs by at most one", () => {
+  const ids = interpolate(lo, hi, 7);
+  const steps: bigint[] = [];
+  for (let i = 1; i < ids.length; i++) {
+    steps.push(ksuidToBigInt(ids[i]) - ksuidToBigInt(ids[i - 1]));
+  }
+
+  const min = steps.reduce((m, s) => (s < m ? s : m));
+  const max = steps.reduce((m, s) => (s > m ? s : m));
+  assert.ok(max - min <= 1n);
+});
+
+test("interpolate() with n < 2", () => {
+  assert.equal(interpolate(lo, hi, 0), []);
+  assert.equal(interpolate(lo, hi, -3), []);
+
+  const single = interpolate(lo, hi, 1);
+  assert.is(single.length, 1);
+  assert.is(single[0].toString(), lo.toString());
+});
+
+test("interpolate() with lo > hi descends", () => {
+  const ids = interpolate(hi, lo, 4);
+  assert.is(ids[0].toString(), hi.toString());
+  assert.is(ids[3].toString(), lo.toString());
+  for (let i = 1; i < ids.length; i++) {
+    assert.is(ids[i].compare(ids[i - 1]), -1);
+  }
+});
+
+test("estimateCount() over half the range", () => {
+  const fullLo = KSUID.nil;
+  const fullHi = KSUID.fromBytes(Buffer.alloc(20, 0xff));
+  const mid = KSUID.fromBytes(
+    Buffer.concat([Buffer.from([0x7f]), Buffer.alloc(19, 0xff)])
+  );
+
+  const estimate = estimateCount(fullLo, mid, 1000000n, fullLo, fullHi);
+  assert.is(estimate, 500000n);
+});
+
+test("estimateCount() over the full range returns the total", () => {
+  assert.is(estimateCount(lo, hi, 12345n, lo, hi), 12345n);
+});
+
+test("estimateCount() with an inverted range returns zero", () => {
+  assert.is(estimateCount(hi, lo, 1000n, lo, hi), 0n);
+});
+
+test("commonPrefixBits() of equal KSUIDs is 160", () => {
+  const k = KSUID.random();
+  assert.is(commonPrefixBits(k, KSUID.fromBytes(k.toBuffer())), 160);
+});
+
+test("commonPrefixBits() of adjacent KSUIDs", () => {
+  const even = KSUID.fromParts(95004740, Buffer.alloc(16, 0x10));
+  assert.is(commonPrefixBits(even, even.next()), 159);
+
+  const carry = KSUID.fromParts(95004740, Buffer.alloc(16, 0xff));
+  assert.is(commonPrefixBits(carry, carry.next()), 31);
+});
+
+test("commonPrefixBits() of far-apart KSUIDs", () => {
+  const max = KSUID.fromBytes(Buffer.alloc(20, 0xff));
+  assert.is(commonPrefixBits(KSUID.nil, max), 0);
+
+  const a = KSUID.fromParts(0x00ff0000, Buffer.alloc(16));
+  const b = KSUID.fromParts(0x00800000, Buffer.alloc(16));
+  assert.is(commonPrefixBits(a, b), 9);
+});
+
+test("nthInRange() at the range boundaries", () => {
+  assert.is(nthInRange(lo, hi, 0n).compare(lo), 0);
+
+  const last = ksuidToBigInt(hi) - ksuidToBigInt(lo);
+  assert.is(nthInRange(lo, hi, last).compare(hi), 0);
+  assert.is(nthInRange(lo, hi, 1n).compare(lo.next()), 0);
+  assert.is(nthInRange(lo, lo, 0n).compare(lo), 0);
+});
+
+test("nthInRange() rejects out-of-range ranks", () => {
+  const last = ksuidToBigInt(hi) - ksuidToBigInt(lo);
+  assert.throws(() => nthInRange(lo, hi, last + 1n), /Invalid rank/);
+  assert.throws(() => nthInRange(lo, hi, -1n), /Invalid rank/);
+  assert.throws(() => nthInRange(hi, lo, 0n), /range of 0 KSUIDs/);
+});
+
+test("between() of a wide gap returns the midpoint", () => {
+  const mid = between(lo, hi);
+  assert.is(lo.compare(mid), -1);
+  assert.is(mid.compare(hi), -1);
+
+  const expected = (ksuidToBigInt(lo) + ksuidToBigInt(hi)) / 2n;
+  assert.is(ksuidToBigInt(mid), expected);
+});
+
+test("between() of a gap of two returns the single value", () => {
+  assert.is(between(lo, lo.next().next()).compare(lo.next()), 0);
+});
+
+test("between() of adjacent or inverted KSUIDs throws", () => {
+  assert.throws(() => between(lo, lo.next()), /No KSUID exists/);
+  assert.throws(() => between(lo, lo), /No KSUID exists/);
+  assert.throws(() => between(hi, lo), /No KSUID exists/);
+});
+
+test("distance() is symmetric and zero for identical KSUIDs", () => {
+  assert.is(distance(lo, lo), 0n);
+  assert.is(distance(lo, lo.next()), 1n);
+  assert.is(distance(lo.next(), lo), 1n);
+  assert.is(distance(lo, hi), distance(hi, lo));
+  assert.is(distance(lo, hi), ksuidToBigInt(hi) - ksuidToBigInt(lo));
+});
+
+test("distance() spans the full 160-bit range", () => {
+  const max = KSUID.fromBytes(Buffer.alloc(20, 0xff));
+  assert.is(distance(KSUID.nil, max), (1n << 160n) - 1n);
+
+  const a = KSUID.fromParts(95004740, Buffer.alloc(16));
+  const b = KSUID.fromParts(95004741, Buffer.alloc(16));
+  assert.is(distance(a, b), 1n << 128n);
+});
+
+test("range() visits every KSUID from start to end inclusive", () => {
+  const end = lo.nextN(4n);
+  const seen: string[] = [];
+  range(lo, end, id => {
+    seen.push(id.toString());
+  });
+
+  assert.is(seen.length, 5);
+  assert.is(seen[0], lo.toString());
+  assert.is(seen[4], end.toString());
+
+  const single: KSUID[] = [];
+  range(lo, lo, id => {
+    single.push(id);
+  });
+  assert.is(single.length, 1);
+});
+
+test("range() stops early and ignores inverted bounds", () => {
+  let calls = 0;
+  range(lo, lo.nextN(100n), () => ++calls < 3);
+  assert.is(calls, 3);
+
+  range(hi, lo, () => {
+    calls++;
+  });
+  assert.is(calls, 3);
+});
+
+test("range() ending at the maximum KSUID terminates", () => {
+  const max = KSUID.parse("aWgEPTl1tmebfsQzFP4bxwgy80V");
+  let calls = 0;
+  range(max.prevN(2n), max, () => {
+    calls++;
+  });
+  assert.is(calls, 3);
+});
+
+test.run();
@@ -60,6 +60,15 @@ test("Sequence bounds", () => {
   assert.is(min.compare(max), -1);
 });
 
+test("Sequence bounds of a fresh sequence cover the reserved block", () => {
+  const seed = KSUID.fromParts(95004740, Buffer.alloc(16, 0xab));
+  const { min, max } = new Sequence({ seed }).bounds();
+
+  const hex = seed.toBuffer().toString("hex").slice(0, 36);
+  assert.is(min.toBuffer().toString("hex"), hex + "0000");
+  assert.is(max.toBuffer().toString("hex"), hex + "ffff");
+});
+
 test("Sequence bounds after some generation", () => {
   const seed = KSUID.random();
   const seq = new Sequence({ seed });
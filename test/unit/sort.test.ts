@@ -1,6 +1,14 @@
 import { test } from "uvu";
 import * as assert from "uvu/assert";
-import { sort, isSorted, compare } from "../../src/sort";
+import {
+  sort,
+  isSorted,
+  compare,
+  compareTime,
+  compareSuffix,
+  merge,
+  equal,
+} from "../../src/sort";
 import { KSUID } from "../../src/ksuid";
 import { Buffer } from "buffer";
 
@@ -199,4 +207,72 @@ test("sort() performance with large array", () => {
   );
 });
 
+test("compareSuffix() compares IDs differing only in the suffix", () => {
+  const payload = Buffer.from("669f7efd7b6fe812278486085878563d", "hex");
+  const a = KSUID.fromParts(95004740, payload);
+  const b = a.next();
+
+  assert.is(compareSuffix(a, b, 4), -1);
+  assert.is(compareSuffix(b, a, 4), 1);
+  assert.is(compareSuffix(a, a, 4), 0);
+  assert.is(compareSuffix(a, b, 19), -1);
+  assert.is(compareSuffix(a, b, 0), compare(a, b));
+});
+
+test("compareSuffix() ignores the skipped prefix", () => {
+  const early = KSUID.fromParts(95004740, Buffer.alloc(16, 0xff));
+  const late = KSUID.fromParts(95004741, Buffer.alloc(16, 0x00));
+
+  assert.is(compare(early, late), -1);
+  assert.is(compareSuffix(early, late, 4), 1);
+  assert.is(compareSuffix(early, late, 20), 0);
+});
+
+test("compareSuffix() rejects invalid skipBytes", () => {
+  const a = KSUID.random();
+  assert.throws(() => compareSuffix(a, a, -1), /Invalid skipBytes/);
+  assert.throws(() => compareSuffix(a, a, 21), /Invalid skipBytes/);
+});
+
+test("merge() interleaves sorted arrays and drops duplicates", () => {
+  const ids = Array.from({ length: 10 }, () => KSUID.random());
+  sort(ids);
+
+  const a = [ids[0], ids[2], ids[3], ids[6], ids[9]];
+  const b = [ids[1], ids[2], ids[4], ids[5], ids[6], ids[7], ids[8]];
+  const merged = merge(a, b);
+
+  assert.ok(equal(merged, ids));
+  assert.ok(isSorted(merged));
+});
+
+test("merge() with empty inputs", () => {
+  const ids = [KSUID.nil, KSUID.random()];
+  assert.equal(merge([], []), []);
+  assert.ok(equal(merge(ids, []), ids));
+  assert.ok(equal(merge([], ids), ids));
+  assert.ok(equal(merge(ids, ids), ids));
+});
+
+test("equal() compares element-wise", () => {
+  const a = [KSUID.random(), KSUID.random()];
+  const copy = a.map(id => KSUID.fromBytes(Buffer.from(id.toBuffer())));
+
+  assert.ok(equal(a, copy));
+  assert.ok(equal([], []));
+  assert.not.ok(equal(a, [a[0]]));
+  assert.not.ok(equal(a, [a[1], a[0]]));
+});
+
+test("compareTime() ignores the payload", () => {
+  const a = KSUID.fromParts(95004740, Buffer.alloc(16, 0xff));
+  const b = KSUID.fromParts(95004740, Buffer.alloc(16));
+  const c = KSUID.fromParts(95004741, Buffer.alloc(16));
+
+  assert.is(compareTime(a, b), 0);
+  assert.is(compare(a, b), 1);
+  assert.is(compareTime(a, c), -1);
+  assert.is(compareTime(c, b), 1);
+});
+
 test.run();
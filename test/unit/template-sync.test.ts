@@ -92,4 +92,43 @@ test("Template: Unknown fields remain unchanged", async () => {
   assert.is(stdout.trim(), "{{ .Unknown }}-0o5sKzFDBc56T8mbUP8wH1KpSX7");
 });
 
+test("Template: hex, unix and rfc3339 functions", async () => {
+  const template =
+    "{{ .Payload | hex }} {{ .Time | unix }} {{.Time|rfc3339}} {{ .Raw | hex }}";
+  const { stdout, stderr } = await execAsync(
+    `npx ts-node src/cli.ts -f template -t "${template}" ${testKSUID}`
+  );
+  assert.is(stderr, "");
+  assert.is(
+    stdout.trim(),
+    "669f7efd7b6fe812278486085878563d 1495004740 2017-05-17T07:05:40Z 05a9a844669f7efd7b6fe812278486085878563d"
+  );
+});
+
+test("Template: functions can be chained", async () => {
+  const { stdout, stderr } = await execAsync(
+    `npx ts-node src/cli.ts -f template -t "{{ .Time | unix | hex }}" ${testKSUID}`
+  );
+  assert.is(stderr, "");
+  assert.is(stdout.trim(), "591bf644");
+});
+
+test("Template: unknown or mismatched functions fail", async () => {
+  for (const [template, message] of [
+    ["{{ .String | nope }}", 'Unknown template function "nope"'],
+    ["{{ .String | unix }}", 'Template function "unix" cannot be applied'],
+  ]) {
+    try {
+      await execAsync(
+        `npx ts-node src/cli.ts -f template -t "${template}" ${testKSUID}`
+      );
+      assert.unreachable("should have failed");
+    } catch (error) {
+      const { code, stderr } = error as { code: number; stderr: string };
+      assert.is(code, 1);
+      assert.ok(stderr.includes(message));
+    }
+  }
+});
+
 test.run();
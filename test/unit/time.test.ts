@@ -0,0 +1,59 @@
+import { test } from "uvu";
+import * as assert from "uvu/assert";
+import { KSUID } from "../../src/ksuid";
+import {
+  overlapDuration,
+  timeToTimestamp,
+  timestampToTime,
+} from "../../src/time";
+import { Buffer } from "buffer";
+
+const second = 1000;
+const a = KSUID.fromParts(95004740, Buffer.alloc(16));
+
+test("overlapDuration() of overlapping windows", () => {
+  const b = KSUID.fromParts(95004750, Buffer.alloc(16));
+  assert.is(overlapDuration(a, 30 * second, b, 60 * second), 20 * second);
+  assert.is(overlapDuration(b, 60 * second, a, 30 * second), 20 * second);
+  assert.is(overlapDuration(a, 60 * second, b, 5 * second), 5 * second);
+});
+
+test("overlapDuration() of adjacent windows is zero", () => {
+  const b = KSUID.fromParts(95004750, Buffer.alloc(16));
+  assert.is(overlapDuration(a, 10 * second, b, 10 * second), 0);
+});
+
+test("overlapDuration() of disjoint windows is zero", () => {
+  const b = KSUID.fromParts(95004800, Buffer.alloc(16));
+  assert.is(overlapDuration(a, 10 * second, b, 10 * second), 0);
+  assert.is(overlapDuration(b, 10 * second, a, 10 * second), 0);
+});
+
+test("timeToTimestamp() and timestampToTime() round trip", () => {
+  const time = new Date("2017-05-17T07:05:40.000Z");
+  assert.is(timeToTimestamp(time), 95004740);
+  assert.is(timeToTimestamp(new Date(time.getTime() + 999)), 95004740);
+  assert.equal(timestampToTime(95004740), time);
+  assert.is(timeToTimestamp(time), a.timestamp);
+});
+
+test("timeToTimestamp() clamps to the uint32 range", () => {
+  assert.is(timeToTimestamp(new Date(0)), 0);
+  assert.is(timeToTimestamp(new Date(1400000000 * 1000)), 0);
+  assert.is(
+    timeToTimestamp(new Date((1400000000 + 2 ** 33) * 1000)),
+    0xffffffff
+  );
+  assert.is(
+    timestampToTime(0xffffffff).toISOString(),
+    "2150-06-19T23:21:35.000Z"
+  );
+});
+
+test("timestampToTime() rejects non-uint32 timestamps", () => {
+  assert.throws(() => timestampToTime(-1), /must be uint32/);
+  assert.throws(() => timestampToTime(2 ** 32), /must be uint32/);
+  assert.throws(() => timestampToTime(1.5), /must be uint32/);
+});
+
+test.run();